@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/husseinelguindi/sudoku-api/sudoku"
+)
+
+// candidatesRequest represents the body of a candidates request.
+type candidatesRequest struct {
+	puzzleRequest
+}
+
+// candidatesResponse represents the body of a successful candidates request.
+type candidatesResponse struct {
+	Candidates sudoku.CandidateGrid `json:"candidates"`
+}
+
+// handleCandidates responds with the candidates (pencil marks) of every cell of the puzzle of the
+// request.
+func (s *Server) handleCandidates(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req candidatesRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, err.Error())
+		return
+	}
+	puzzle, ok := req.newPuzzle(w)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, candidatesResponse{Candidates: puzzle.CandidateGrid()})
+}
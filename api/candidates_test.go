@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCandidates(t *testing.T) {
+	rec := doRequest(t, http.MethodPost, "/v1/candidates", `{
+		"puzzle": [
+			[1, 2, 0, 0],
+			[0, 0, 0, 0],
+			[0, 0, 0, 0],
+			[0, 0, 0, 4]
+		]
+	}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"candidates": [
+		[[], [], [3, 4], [3]],
+		[[3, 4], [3, 4], [1, 2, 3, 4], [1, 2, 3]],
+		[[2, 3, 4], [1, 3, 4], [1, 2, 3], [1, 2, 3]],
+		[[2, 3], [1, 3], [1, 2, 3], []]
+	]}`, rec.Body.String())
+}
+
+func TestCandidatesTooLarge(t *testing.T) {
+	// The response grows with the cube of the side, so large puzzles are rejected on every route.
+	row := "[" + strings.Repeat("0,", maxSide) + "0]"
+	matrix := `{"puzzle": [` + strings.Repeat(row+",", maxSide) + row + `]}`
+	line := `{"line": "` + strings.Repeat(".", 81*81) + `"}`
+	for _, path := range []string{"/v1/candidates", "/v1/hint", "/v1/solve"} {
+		for _, body := range []string{matrix, line} {
+			rec := doRequest(t, http.MethodPost, path, body)
+			require.Equal(t, http.StatusUnprocessableEntity, rec.Code, path)
+			require.Equal(t, codeInvalidPuzzle, decodeError(t, rec).Code, path)
+		}
+	}
+
+	row = "[" + strings.Repeat("0,", maxSide-1) + "0]"
+	matrix = `{"puzzle": [` + strings.Repeat(row+",", maxSide-1) + row + `]}`
+	rec := doRequest(t, http.MethodPost, "/v1/candidates", matrix)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/husseinelguindi/sudoku-api/sudoku"
+)
+
+// hintRequest represents the body of a hint request. Eliminated lists the candidates the player
+// has already ruled out, and is optional.
+type hintRequest struct {
+	puzzleRequest
+	Eliminated []sudoku.Candidate `json:"eliminated,omitempty"`
+}
+
+// hintResponse represents the body of a successful hint request.
+type hintResponse struct {
+	Hint sudoku.Step `json:"hint"`
+}
+
+// handleHint responds with the next logical deduction of the puzzle of the request. The search
+// for a solution is stopped once it exceeds the solve timeout of the server, or the request is
+// canceled.
+func (s *Server) handleHint(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req hintRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, err.Error())
+		return
+	}
+	puzzle, ok := req.newPuzzle(w, sudoku.WithMaxDuration(s.solveTimeout))
+	if !ok {
+		return
+	}
+
+	step, err := puzzle.HintContext(r.Context(), req.Eliminated...)
+	switch {
+	case errors.Is(err, sudoku.ErrUnsolvable):
+		writeError(w, http.StatusUnprocessableEntity, codeUnsolvable, "puzzle has no solution")
+	case errors.Is(err, sudoku.ErrBudgetExceeded):
+		writeError(w, http.StatusUnprocessableEntity, codeBudgetExceeded, "puzzle could not be solved in time")
+	case errors.Is(err, sudoku.ErrSolved):
+		writeError(w, http.StatusUnprocessableEntity, codeSolved, err.Error())
+	case errors.Is(err, sudoku.ErrNoHint):
+		writeError(w, http.StatusUnprocessableEntity, codeNoHint, err.Error())
+	case err != nil && r.Context().Err() != nil:
+		// The request was canceled, no one is left to respond to.
+	case err != nil:
+		writeError(w, http.StatusUnprocessableEntity, codeInvalidRequest, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, hintResponse{Hint: step})
+	}
+}
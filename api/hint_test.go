@@ -0,0 +1,85 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/husseinelguindi/sudoku-api/sudoku"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHint(t *testing.T) {
+	rec := doRequest(t, http.MethodPost, "/v1/hint", `{
+		"puzzle": [
+			[1, 2, 0, 0],
+			[0, 0, 0, 0],
+			[0, 0, 0, 0],
+			[0, 0, 0, 0]
+		],
+		"eliminated": [{"row": 0, "col": 2, "value": 4}]
+	}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var res hintResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+	require.Equal(t, sudoku.Step{
+		Technique: sudoku.NakedSingle,
+		Placement: &sudoku.Candidate{Cell: sudoku.Cell{Row: 0, Col: 2}, Value: 3},
+		Cells:     []sudoku.Cell{{Row: 0, Col: 0}, {Row: 0, Col: 1}},
+	}, res.Hint)
+}
+
+func TestHintErrors(t *testing.T) {
+	testCases := []struct {
+		body   string
+		status int
+		code   string
+	}{
+		{`{"puzzle": [[1, 1, 0, 0], [0, 0, 0, 0], [0, 0, 0, 0], [0, 0, 0, 0]]}`, http.StatusUnprocessableEntity, codeConflict},
+		{`{"puzzle": [[1, 2, 0, 0], [0, 0, 3, 4], [0, 0, 0, 0], [0, 0, 0, 0]]}`, http.StatusUnprocessableEntity, codeUnsolvable},
+		{`{"puzzle": [[1, 2, 3, 4], [3, 4, 1, 2], [2, 1, 4, 3], [4, 3, 2, 1]]}`, http.StatusUnprocessableEntity, codeSolved},
+		{`{"line": "123434122143432.", "eliminated": [{"row": 3, "col": 3, "value": 1}]}`, http.StatusUnprocessableEntity, codeInvalidRequest},
+		{`{"puzzle": [[0, 0, 0, 0], [0, 0, 0, 0], [0, 0, 0, 0], [0, 0, 0, 0]], "eliminated": [{"row": 5, "col": 0, "value": 1}]}`, http.StatusUnprocessableEntity, codeInvalidRequest},
+	}
+	for _, tc := range testCases {
+		rec := doRequest(t, http.MethodPost, "/v1/hint", tc.body)
+		require.Equal(t, tc.status, rec.Code, tc.body)
+		require.Equal(t, tc.code, decodeError(t, rec).Code, tc.body)
+	}
+}
+
+func TestHintBudgetExceeded(t *testing.T) {
+	s := NewServer()
+	s.solveTimeout = time.Nanosecond
+
+	// A puzzle without a solution, as the 1s outside of its first box leave no cell of it for 1,
+	// which the search only finds out after a long time.
+	req := httptest.NewRequest(http.MethodPost, "/v1/hint", bytes.NewBufferString(`{
+		"puzzle": [
+			[0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0],
+			[0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0],
+			[0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0],
+			[0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0],
+			[0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0],
+			[1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0],
+			[0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0],
+			[0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0],
+			[0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0],
+			[0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0],
+			[0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0],
+			[0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0],
+			[0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0],
+			[0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0],
+			[0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0],
+			[0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0]
+		]
+	}`))
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	require.Equal(t, codeBudgetExceeded, decodeError(t, rec).Code)
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/husseinelguindi/sudoku-api/sudoku"
+)
+
+// puzzleRequest represents the puzzle of a request body, either as a matrix (Puzzle) or in the
+// line format of sudoku.ParseLine (Line). The box dimensions, jigsaw regions, Killer cages, and
+// X-Sudoku diagonals of a matrix are optional, with box dimensions defaulting to the square root of
+// the puzzle side, while those of a line are part of the line.
+type puzzleRequest struct {
+	Puzzle    [][]sudoku.PuzzleInt `json:"puzzle"`
+	Line      string               `json:"line,omitempty"`
+	BoxHeight sudoku.PuzzleInt     `json:"box_height,omitempty"`
+	BoxWidth  sudoku.PuzzleInt     `json:"box_width,omitempty"`
+	Regions   [][]sudoku.PuzzleInt `json:"regions,omitempty"`
+	Cages     []sudoku.Cage        `json:"cages,omitempty"`
+	Diagonals bool                 `json:"diagonals,omitempty"`
+}
+
+// opts returns the puzzle options described by the request.
+func (req puzzleRequest) opts() []sudoku.PuzzleOption {
+	var opts []sudoku.PuzzleOption
+	if req.BoxHeight != 0 || req.BoxWidth != 0 {
+		opts = append(opts, sudoku.WithBoxDimensions(req.BoxHeight, req.BoxWidth))
+	}
+	if req.Regions != nil {
+		opts = append(opts, sudoku.WithRegions(req.Regions))
+	}
+	if len(req.Cages) != 0 {
+		opts = append(opts, sudoku.WithCages(req.Cages...))
+	}
+	if req.Diagonals {
+		opts = append(opts, sudoku.WithDiagonals())
+	}
+	return opts
+}
+
+// errPuzzleSize is returned for puzzles with a side larger than maxSide.
+var errPuzzleSize = fmt.Errorf("puzzle side must be at most %d", maxSide)
+
+// newPuzzle constructs the puzzle of the request, with the options of the request followed by
+// opts, ensuring its side is at most maxSide and its values do not conflict. Otherwise, an error
+// response is written and ok is false.
+func (req puzzleRequest) newPuzzle(w http.ResponseWriter, opts ...sudoku.PuzzleOption) (puzzle sudoku.Puzzle, ok bool) {
+	var err error
+	switch {
+	case len(req.Puzzle) > maxSide:
+		err = errPuzzleSize
+	case req.Line != "" && (req.Puzzle != nil || req.BoxHeight != 0 || req.BoxWidth != 0 ||
+		req.Regions != nil || req.Cages != nil || req.Diagonals):
+		err = errors.New("line cannot be combined with puzzle, box dimensions, regions, cages, or diagonals")
+	case req.Line != "":
+		puzzle, err = sudoku.ParseLine(req.Line, opts...)
+	default:
+		puzzle, err = sudoku.NewPuzzle(req.Puzzle, append(req.opts(), opts...)...)
+	}
+	if err == nil && len(puzzle.Arr) > maxSide {
+		err = errPuzzleSize
+	}
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, codeInvalidPuzzle, err.Error())
+		return sudoku.Puzzle{}, false
+	}
+	if conflicts := puzzle.Validate(); len(conflicts) != 0 {
+		writeConflicts(w, conflicts)
+		return sudoku.Puzzle{}, false
+	}
+	return puzzle, true
+}
@@ -0,0 +1,113 @@
+// Package api implements the HTTP interface of the Sudoku puzzle service.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/husseinelguindi/sudoku-api/sudoku"
+)
+
+// maxBodySize is the maximum number of bytes read from a request body.
+const maxBodySize = 1 << 20
+
+// maxSide is the largest puzzle side accepted, bounding the work of a request and the size of its
+// response, which grow faster than its body.
+const maxSide = 64
+
+// defaultSolveTimeout is the longest a solve request may search for.
+const defaultSolveTimeout = 5 * time.Second
+
+// Server represents the HTTP server of the Sudoku API, routing requests to their handlers.
+type Server struct {
+	mux          *http.ServeMux
+	solveTimeout time.Duration
+}
+
+// NewServer returns a reference to a Server object with all of its routes registered.
+func NewServer() *Server {
+	s := &Server{mux: http.NewServeMux(), solveTimeout: defaultSolveTimeout}
+	s.mux.HandleFunc("/v1/solve", s.handleSolve)
+	s.mux.HandleFunc("/v1/hint", s.handleHint)
+	s.mux.HandleFunc("/v1/candidates", s.handleCandidates)
+	return s
+}
+
+// ServeHTTP implements the http.Handler interface for Server.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Error codes returned in the body of unsuccessful responses.
+const (
+	codeMethodNotAllowed = "method_not_allowed"
+	codeInvalidRequest   = "invalid_request"
+	codeInvalidPuzzle    = "invalid_puzzle"
+	codeConflict         = "conflicting_values"
+	codeUnsolvable       = "unsolvable"
+	codeSolved           = "already_solved"
+	codeNoHint           = "no_hint"
+	codeBudgetExceeded   = "budget_exceeded"
+)
+
+// apiError represents the structured error returned by all endpoints. Conflicts is only set
+// for puzzles with conflicting values.
+type apiError struct {
+	Code      string            `json:"code"`
+	Message   string            `json:"message"`
+	Conflicts []sudoku.Conflict `json:"conflicts,omitempty"`
+}
+
+// errorResponse represents the body of an unsuccessful response.
+type errorResponse struct {
+	Error apiError `json:"error"`
+}
+
+// writeJSON encodes v into the response body with the passed status code.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// The status is already sent, nothing can be done about an encoding error.
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a structured error response with the passed status code.
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, errorResponse{Error: apiError{Code: code, Message: message}})
+}
+
+// writeConflicts writes an error response listing the conflicting values of a puzzle.
+func writeConflicts(w http.ResponseWriter, conflicts []sudoku.Conflict) {
+	writeJSON(w, http.StatusUnprocessableEntity, errorResponse{Error: apiError{
+		Code:      codeConflict,
+		Message:   "puzzle contains conflicting values",
+		Conflicts: conflicts,
+	}})
+}
+
+// decodeJSON decodes the request body into v, rejecting unknown fields and oversized bodies.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	// Only a single JSON value is accepted.
+	if dec.More() {
+		return errors.New("request body must only contain a single JSON object")
+	}
+	return nil
+}
+
+// allowMethod ensures the request uses method, otherwise an error response is written and false
+// is returned.
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, codeMethodNotAllowed, "method must be "+method)
+	return false
+}
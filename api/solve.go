@@ -0,0 +1,57 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/husseinelguindi/sudoku-api/sudoku"
+)
+
+// solveRequest represents the body of a solve request.
+type solveRequest struct {
+	puzzleRequest
+}
+
+// solveResponse represents the body of a successful solve request. Line is the solution in the
+// line format, omitted for puzzles too large for it. The regions of jigsaw puzzles and the cages
+// of Killer puzzles are returned alongside the solution.
+type solveResponse struct {
+	Solution [][]sudoku.PuzzleInt `json:"solution"`
+	Line     string               `json:"line,omitempty"`
+	Regions  [][]sudoku.PuzzleInt `json:"regions,omitempty"`
+	Cages    []sudoku.Cage        `json:"cages,omitempty"`
+}
+
+// handleSolve solves the puzzle of the request, responding with the solved grid. The search is
+// stopped once it exceeds the solve timeout of the server, or the request is canceled.
+func (s *Server) handleSolve(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req solveRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, err.Error())
+		return
+	}
+	puzzle, ok := req.newPuzzle(w, sudoku.WithMaxDuration(s.solveTimeout))
+	if !ok {
+		return
+	}
+
+	_, err := puzzle.SolveContext(r.Context())
+	switch {
+	case errors.Is(err, sudoku.ErrUnsolvable):
+		writeError(w, http.StatusUnprocessableEntity, codeUnsolvable, "puzzle has no solution")
+	case errors.Is(err, sudoku.ErrBudgetExceeded):
+		writeError(w, http.StatusUnprocessableEntity, codeBudgetExceeded, "puzzle could not be solved in time")
+	case err != nil:
+		// The request was canceled, no one is left to respond to.
+	default:
+		res := solveResponse{Solution: puzzle.Arr, Regions: puzzle.Regions(), Cages: puzzle.Cages()}
+		if line, err := puzzle.MarshalText(); err == nil {
+			res.Line = string(line)
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
@@ -0,0 +1,255 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/husseinelguindi/sudoku-api/sudoku"
+	"github.com/stretchr/testify/require"
+)
+
+// doRequest sends a request with body to the server and returns the recorded response.
+func doRequest(t *testing.T, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+	NewServer().ServeHTTP(rec, req)
+	return rec
+}
+
+// decodeError decodes the structured error of an unsuccessful response.
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) apiError {
+	var res errorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+	return res.Error
+}
+
+func TestSolve(t *testing.T) {
+	rec := doRequest(t, http.MethodPost, "/v1/solve", `{
+		"puzzle": [
+			[5, 1, 0, 0, 2, 0],
+			[0, 0, 4, 0, 0, 0],
+			[0, 0, 2, 0, 0, 0],
+			[0, 0, 0, 0, 6, 5],
+			[0, 0, 5, 0, 0, 0],
+			[0, 0, 0, 0, 1, 3]
+		],
+		"box_height": 2,
+		"box_width": 3
+	}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var res solveResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+	require.Equal(t, [][]sudoku.PuzzleInt{
+		{5, 1, 3, 4, 2, 6},
+		{2, 6, 4, 3, 5, 1},
+		{6, 5, 2, 1, 3, 4},
+		{3, 4, 1, 2, 6, 5},
+		{1, 3, 5, 6, 4, 2},
+		{4, 2, 6, 5, 1, 3},
+	}, res.Solution)
+	require.Equal(t, "2x3:513426264351652134341265135642426513", res.Line)
+}
+
+func TestSolveLine(t *testing.T) {
+	rec := doRequest(t, http.MethodPost, "/v1/solve", `{"line": "2x3:51..2...4.....2.......65..5.......13"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var res solveResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+	require.Equal(t, "2x3:513426264351652134341265135642426513", res.Line)
+	require.Equal(t, []sudoku.PuzzleInt{5, 1, 3, 4, 2, 6}, res.Solution[0])
+
+	testCases := []string{
+		`{"line": "1.2"}`,
+		`{"line": "1...............", "puzzle": [[1]]}`,
+		`{"line": "1...............", "box_height": 2}`,
+		`{"line": "1...............", "regions": []}`,
+		`{"line": "1...............", "cages": []}`,
+		`{"line": "1...............", "diagonals": true}`,
+	}
+	for _, body := range testCases {
+		rec := doRequest(t, http.MethodPost, "/v1/solve", body)
+		require.Equal(t, http.StatusUnprocessableEntity, rec.Code, body)
+		require.Equal(t, codeInvalidPuzzle, decodeError(t, rec).Code, body)
+	}
+}
+
+func TestSolveCages(t *testing.T) {
+	rec := doRequest(t, http.MethodPost, "/v1/solve", `{
+		"puzzle": [
+			[0, 0, 0, 0],
+			[0, 0, 0, 0],
+			[0, 0, 0, 0],
+			[0, 0, 0, 0]
+		],
+		"cages": [
+			{"sum": 6, "cells": [{"row": 0, "col": 2}, {"row": 1, "col": 2}, {"row": 2, "col": 2}]},
+			{"sum": 5, "cells": [{"row": 1, "col": 1}, {"row": 2, "col": 1}]},
+			{"sum": 4, "cells": [{"row": 0, "col": 0}, {"row": 1, "col": 0}]},
+			{"sum": 2, "cells": [{"row": 0, "col": 1}]},
+			{"sum": 7, "cells": [{"row": 0, "col": 3}, {"row": 1, "col": 3}]},
+			{"sum": 10, "cells": [{"row": 2, "col": 3}, {"row": 3, "col": 1}, {"row": 3, "col": 2}, {"row": 3, "col": 3}]},
+			{"sum": 6, "cells": [{"row": 2, "col": 0}, {"row": 3, "col": 0}]}
+		]
+	}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var res solveResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+	require.Equal(t, [][]sudoku.PuzzleInt{
+		{3, 2, 1, 4},
+		{1, 4, 2, 3},
+		{4, 1, 3, 2},
+		{2, 3, 4, 1},
+	}, res.Solution)
+	require.Equal(t, "3214142341322341;2+6+10=6;5+9=5;0+4=4;1=2;3+7=7;11+13+14+15=10;8+12=6", res.Line)
+	require.Len(t, res.Cages, 7)
+	require.Equal(t, sudoku.Cage{Sum: 2, Cells: []sudoku.Cell{{Row: 0, Col: 1}}}, res.Cages[3])
+
+	// Malformed cages are rejected with the puzzle.
+	rec = doRequest(t, http.MethodPost, "/v1/solve", `{
+		"puzzle": [[0, 0, 0, 0], [0, 0, 0, 0], [0, 0, 0, 0], [0, 0, 0, 0]],
+		"cages": [{"sum": 9, "cells": [{"row": 0, "col": 0}, {"row": 0, "col": 1}]}]
+	}`)
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	require.Equal(t, codeInvalidPuzzle, decodeError(t, rec).Code)
+}
+
+func TestSolveDiagonals(t *testing.T) {
+	rec := doRequest(t, http.MethodPost, "/v1/solve", `{
+		"puzzle": [
+			[0, 0, 0, 0, 0, 0],
+			[0, 0, 0, 0, 0, 0],
+			[0, 4, 0, 0, 0, 0],
+			[0, 0, 0, 0, 0, 0],
+			[0, 0, 0, 6, 0, 1],
+			[0, 3, 0, 0, 2, 0]
+		],
+		"box_height": 2,
+		"box_width": 3,
+		"diagonals": true
+	}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// The line keeps the diagonals, solving to the same grid.
+	var res solveResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+	require.Equal(t, "X:2x3:412356365142241563653214524631136425", res.Line)
+
+	rec = doRequest(t, http.MethodPost, "/v1/solve", `{"line": "X:2x3:.............4.............6.1.3..2."}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var lineRes solveResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&lineRes))
+	require.Equal(t, res, lineRes)
+}
+
+func TestSolveRegions(t *testing.T) {
+	rec := doRequest(t, http.MethodPost, "/v1/solve", `{
+		"puzzle": [
+			[0, 2, 4, 0, 5],
+			[0, 0, 0, 0, 0],
+			[0, 0, 0, 0, 0],
+			[0, 0, 0, 0, 0],
+			[1, 0, 0, 0, 0]
+		],
+		"regions": [
+			[0, 0, 0, 1, 1],
+			[0, 0, 2, 1, 1],
+			[3, 2, 2, 2, 1],
+			[3, 3, 4, 2, 4],
+			[3, 3, 4, 4, 4]
+		]
+	}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var res solveResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+	require.Equal(t, [][]sudoku.PuzzleInt{
+		{3, 2, 4, 1, 5},
+		{5, 1, 3, 4, 2},
+		{2, 4, 1, 5, 3},
+		{4, 3, 5, 2, 1},
+		{1, 5, 2, 3, 4},
+	}, res.Solution)
+	require.Equal(t, "0001100211322213342433444:3241551342241534352115234", res.Line)
+	require.Equal(t, []sudoku.PuzzleInt{3, 3, 4, 4, 4}, res.Regions[4])
+
+	// Regions of the wrong size are rejected with the puzzle.
+	rec = doRequest(t, http.MethodPost, "/v1/solve", `{
+		"puzzle": [[0, 0, 0, 0], [0, 0, 0, 0], [0, 0, 0, 0], [0, 0, 0, 0]],
+		"regions": [[0, 0, 0, 0], [0, 1, 1, 1], [2, 2, 2, 2], [3, 3, 3, 3]]
+	}`)
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	require.Equal(t, codeInvalidPuzzle, decodeError(t, rec).Code)
+}
+
+func TestSolveErrors(t *testing.T) {
+	testCases := []struct {
+		method, body string
+		status       int
+		code         string
+	}{
+		{http.MethodGet, "", http.StatusMethodNotAllowed, codeMethodNotAllowed},
+		{http.MethodPost, "{", http.StatusBadRequest, codeInvalidRequest},
+		{http.MethodPost, `{"grid": []}`, http.StatusBadRequest, codeInvalidRequest},
+		{http.MethodPost, `{"puzzle": []}`, http.StatusUnprocessableEntity, codeInvalidPuzzle},
+		{http.MethodPost, `{"puzzle": [[0, 0], [0]]}`, http.StatusUnprocessableEntity, codeInvalidPuzzle},
+		{http.MethodPost, `{"puzzle": [[0, 0, 0], [0, 0, 0]]}`, http.StatusUnprocessableEntity, codeInvalidPuzzle},
+		{http.MethodPost, `{"puzzle": [[0, 0], [0, 0]], "box_height": 3}`, http.StatusUnprocessableEntity, codeInvalidPuzzle},
+		{http.MethodPost, `{"puzzle": [[0, 0], [0, 3]]}`, http.StatusUnprocessableEntity, codeInvalidPuzzle},
+		{http.MethodPost, `{"puzzle": [[1, 1, 0, 0], [0, 0, 0, 0], [0, 0, 0, 0], [0, 0, 0, 0]]}`, http.StatusUnprocessableEntity, codeConflict},
+		{http.MethodPost, `{"puzzle": [[1, 2, 0, 0], [0, 0, 3, 4], [0, 0, 0, 0], [0, 0, 0, 0]]}`, http.StatusUnprocessableEntity, codeUnsolvable},
+	}
+	for _, tc := range testCases {
+		rec := doRequest(t, tc.method, "/v1/solve", tc.body)
+		require.Equal(t, tc.status, rec.Code, tc.body)
+		require.Equal(t, tc.code, decodeError(t, rec).Code, tc.body)
+	}
+}
+
+func TestSolveConflicts(t *testing.T) {
+	rec := doRequest(t, http.MethodPost, "/v1/solve", `{
+		"puzzle": [
+			[1, 0, 0, 1],
+			[0, 0, 0, 0],
+			[0, 0, 0, 0],
+			[0, 0, 0, 0]
+		]
+	}`)
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	apiErr := decodeError(t, rec)
+	require.Equal(t, codeConflict, apiErr.Code)
+	require.Equal(t, []sudoku.Conflict{
+		{Unit: sudoku.UnitRow, Value: 1, A: sudoku.Cell{Row: 0, Col: 0}, B: sudoku.Cell{Row: 0, Col: 3}},
+	}, apiErr.Conflicts)
+}
+
+func TestSolveBudgetExceeded(t *testing.T) {
+	s := NewServer()
+	s.solveTimeout = time.Nanosecond
+
+	// A puzzle that needs many guesses, checking the deadline along the way.
+	req := httptest.NewRequest(http.MethodPost, "/v1/solve", bytes.NewBufferString(`{
+		"puzzle": [
+			[8, 0, 0, 4, 0, 0, 9, 1, 0],
+			[0, 0, 3, 0, 0, 0, 0, 0, 0],
+			[0, 0, 0, 0, 0, 3, 0, 0, 4],
+			[0, 0, 0, 0, 0, 1, 0, 4, 0],
+			[0, 5, 8, 0, 0, 0, 7, 0, 0],
+			[0, 7, 0, 0, 0, 6, 8, 0, 0],
+			[0, 0, 0, 0, 0, 2, 0, 0, 0],
+			[0, 0, 0, 0, 0, 0, 1, 6, 0],
+			[9, 1, 0, 0, 6, 0, 5, 0, 0]
+		]
+	}`))
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	require.Equal(t, codeBudgetExceeded, decodeError(t, rec).Code)
+}
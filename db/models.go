@@ -7,9 +7,11 @@ import (
 )
 
 type Puzzle struct {
-	ID        int64
-	ArrayStr  string
-	CreatedAt time.Time
+	ID           int64
+	ArrayStr     string
+	CanonicalStr string
+	Score        int32
+	CreatedAt    time.Time
 }
 
 type User struct {
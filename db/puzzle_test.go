@@ -6,16 +6,73 @@ import (
 	"time"
 
 	"github.com/brianvoe/gofakeit/v6"
+	"github.com/husseinelguindi/sudoku-api/sudoku"
 	"github.com/stretchr/testify/require"
 )
 
+// testSolution is the complete 9x9 grid, in the line format, that random test puzzles are
+// derived from.
+const testSolution = "534678912672195348198342567859761423426853791713924856961537284287419635345286179"
+
+// randomPuzzleLine returns a random 9x9 puzzle in the line format of sudoku.Puzzle, relabelling
+// the digits of testSolution and vacating random positions. The line is guaranteed to be valid.
+// Otherwise, the test (t) is failed.
+func randomPuzzleLine(t *testing.T) string {
+	digits := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	gofakeit.ShuffleInts(digits)
+
+	line := []byte(testSolution)
+	for i, c := range line {
+		if gofakeit.Bool() {
+			line[i] = '.'
+		} else {
+			line[i] = byte('0' + digits[c-'1'])
+		}
+	}
+
+	// Ensure the line round trips through the puzzle encoding.
+	puzzle, err := sudoku.ParseLine(string(line))
+	require.NoError(t, err)
+	require.Empty(t, puzzle.Validate())
+	text, err := puzzle.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, string(line), string(text))
+	return string(text)
+}
+
+// canonicalLine returns the line format of the canonical form of the puzzle of line. Otherwise,
+// the test (t) is failed.
+func canonicalLine(t *testing.T, line string) string {
+	puzzle, err := sudoku.ParseLine(line)
+	require.NoError(t, err)
+	canonical, err := puzzle.Canonical()
+	require.NoError(t, err)
+	text, err := canonical.MarshalText()
+	require.NoError(t, err)
+	return string(text)
+}
+
+// randomPuzzleParams returns the params of a random puzzle with score.
+func randomPuzzleParams(t *testing.T, score int32) CreatePuzzleParams {
+	line := randomPuzzleLine(t)
+	return CreatePuzzleParams{
+		ArrayStr:     line,
+		CanonicalStr: canonicalLine(t, line),
+		Score:        score,
+	}
+}
+
 // createRandomPuzzle generates a random puzzle and inserts it into the testQueries database.
 // The inserted puzzle is returned and guaranteed to be valid. Otherwise, the test (t) is failed.
 func createRandomPuzzle(t *testing.T) Puzzle {
-	// Generate a random string of 25 ASCII letters
-	arrayStr := gofakeit.LetterN(25)
+	return createTestPuzzle(t, randomPuzzleParams(t, int32(gofakeit.Number(0, 5000))))
+}
+
+// createTestPuzzle inserts a puzzle with params into the testQueries database. The inserted puzzle
+// is returned and guaranteed to be valid. Otherwise, the test (t) is failed.
+func createTestPuzzle(t *testing.T, params CreatePuzzleParams) Puzzle {
 	// Insert puzzle into db
-	puzzle, err := testQueries.CreatePuzzle(context.Background(), arrayStr)
+	puzzle, err := testQueries.CreatePuzzle(context.Background(), params)
 
 	// Validate the inserted values
 	require.NoError(t, err)
@@ -23,7 +80,9 @@ func createRandomPuzzle(t *testing.T) Puzzle {
 	require.NotZero(t, puzzle.ID)
 
 	// Compare the inserted values with the original values
-	require.Equal(t, puzzle.ArrayStr, arrayStr)
+	require.Equal(t, puzzle.ArrayStr, params.ArrayStr)
+	require.Equal(t, puzzle.CanonicalStr, params.CanonicalStr)
+	require.Equal(t, puzzle.Score, params.Score)
 	require.WithinDuration(t, puzzle.CreatedAt, time.Now(), testTimeThreshold)
 
 	return puzzle
@@ -52,3 +111,59 @@ func TestGetPuzzleByArrayStr(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, insertPuzzle, getPuzzle)
 }
+
+// TestGetPuzzleByCanonicalStr inserts a random puzzle into the db and attempts to query it by its
+// CanonicalStr. The test fails if no puzzle was returned or it does not match the inserted puzzle.
+func TestGetPuzzleByCanonicalStr(t *testing.T) {
+	insertPuzzle := createRandomPuzzle(t)
+	getPuzzle, err := testQueries.GetPuzzleByCanonicalStr(context.Background(), insertPuzzle.CanonicalStr)
+
+	require.NoError(t, err)
+	require.Equal(t, insertPuzzle, getPuzzle)
+}
+
+// TestCreateDisguisedPuzzle inserts a random puzzle into the db, then attempts to insert it again
+// with its rows reversed, which is an equivalent puzzle. The test fails if the second insertion
+// does not violate the unique canonical_str constraint.
+func TestCreateDisguisedPuzzle(t *testing.T) {
+	insertPuzzle := createRandomPuzzle(t)
+
+	// Reversing the rows reverses the bands and the rows within each band.
+	line := []byte(insertPuzzle.ArrayStr)
+	disguised := make([]byte, 0, len(line))
+	for row := 8; row >= 0; row-- {
+		disguised = append(disguised, line[row*9:(row+1)*9]...)
+	}
+	require.NotEqual(t, insertPuzzle.ArrayStr, string(disguised))
+	require.Equal(t, insertPuzzle.CanonicalStr, canonicalLine(t, string(disguised)))
+
+	_, err := testQueries.CreatePuzzle(context.Background(), CreatePuzzleParams{
+		ArrayStr:     string(disguised),
+		CanonicalStr: canonicalLine(t, string(disguised)),
+		Score:        insertPuzzle.Score,
+	})
+	require.Error(t, err)
+}
+
+// TestListPuzzlesByScore inserts puzzles with consecutive scores and lists them by a range of
+// scores. The test fails if the listed puzzles are not the inserted puzzles, ordered by score.
+func TestListPuzzlesByScore(t *testing.T) {
+	var (
+		n = 10 // The number of puzzles to insert and list
+		// A random range of scores, well above the scores of other random puzzles
+		minScore = int32(gofakeit.Number(1e6, 1e9))
+		inserted = make([]Puzzle, n)
+	)
+
+	for i := range inserted {
+		inserted[i] = createTestPuzzle(t, randomPuzzleParams(t, minScore+int32(i)))
+	}
+
+	listed, err := testQueries.ListPuzzlesByScore(context.Background(), ListPuzzlesByScoreParams{
+		MinScore: minScore,
+		MaxScore: minScore + int32(n) - 1,
+		RowLimit: int32(n),
+	})
+	require.NoError(t, err)
+	require.Equal(t, inserted, listed)
+}
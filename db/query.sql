@@ -10,10 +10,14 @@ CREATE INDEX ON users(username);
 
 CREATE TABLE puzzles(
 	id BIGSERIAL PRIMARY KEY,
-	array_str TEXT UNIQUE NOT NULL,
+	array_str TEXT UNIQUE NOT NULL, -- Line format of sudoku.Puzzle, with any Killer cages
+	canonical_str TEXT UNIQUE NOT NULL, -- Line format of the canonical form of sudoku.Puzzle
+	score INTEGER NOT NULL DEFAULT 0,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
 );
 CREATE INDEX ON puzzles(array_str);
+CREATE INDEX ON puzzles(canonical_str);
+CREATE INDEX ON puzzles(score);
 
 CREATE TABLE user_puzzles(
 	user_id BIGSERIAL NOT NULL,
@@ -55,12 +59,20 @@ WHERE username = $1;
 
 -- name: CreatePuzzle :one
 INSERT INTO puzzles (
-	array_str
+	array_str, canonical_str, score
 ) VALUES (
-	$1
+	$1, $2, $3
 )
 RETURNING *;
 
+-- name: ImportPuzzle :execrows
+INSERT INTO puzzles (
+	array_str, canonical_str, score
+) VALUES (
+	$1, $2, $3
+)
+ON CONFLICT DO NOTHING;
+
 -- name: GetPuzzleByID :one
 SELECT * FROM puzzles
 WHERE id = $1 LIMIT 1;
@@ -69,6 +81,16 @@ WHERE id = $1 LIMIT 1;
 SELECT * FROM puzzles
 WHERE array_str = $1 LIMIT 1;
 
+-- name: GetPuzzleByCanonicalStr :one
+SELECT * FROM puzzles
+WHERE canonical_str = $1 LIMIT 1;
+
+-- name: ListPuzzlesByScore :many
+SELECT * FROM puzzles
+WHERE score BETWEEN sqlc.arg(min_score) AND sqlc.arg(max_score)
+ORDER BY score, id
+LIMIT sqlc.arg(row_limit);
+
 
 -- name: CreateUserPuzzle :one
 INSERT INTO user_puzzles (
@@ -9,17 +9,29 @@ import (
 
 const createPuzzle = `-- name: CreatePuzzle :one
 INSERT INTO puzzles (
-	array_str
+	array_str, canonical_str, score
 ) VALUES (
-	$1
+	$1, $2, $3
 )
-RETURNING id, array_str, created_at
+RETURNING id, array_str, canonical_str, score, created_at
 `
 
-func (q *Queries) CreatePuzzle(ctx context.Context, arrayStr string) (Puzzle, error) {
-	row := q.db.QueryRowContext(ctx, createPuzzle, arrayStr)
+type CreatePuzzleParams struct {
+	ArrayStr     string
+	CanonicalStr string
+	Score        int32
+}
+
+func (q *Queries) CreatePuzzle(ctx context.Context, arg CreatePuzzleParams) (Puzzle, error) {
+	row := q.db.QueryRowContext(ctx, createPuzzle, arg.ArrayStr, arg.CanonicalStr, arg.Score)
 	var i Puzzle
-	err := row.Scan(&i.ID, &i.ArrayStr, &i.CreatedAt)
+	err := row.Scan(
+		&i.ID,
+		&i.ArrayStr,
+		&i.CanonicalStr,
+		&i.Score,
+		&i.CreatedAt,
+	)
 	return i, err
 }
 
@@ -115,26 +127,56 @@ func (q *Queries) DeleteUserPuzzle(ctx context.Context, arg DeleteUserPuzzlePara
 }
 
 const getPuzzleByArrayStr = `-- name: GetPuzzleByArrayStr :one
-SELECT id, array_str, created_at FROM puzzles
+SELECT id, array_str, canonical_str, score, created_at FROM puzzles
 WHERE array_str = $1 LIMIT 1
 `
 
 func (q *Queries) GetPuzzleByArrayStr(ctx context.Context, arrayStr string) (Puzzle, error) {
 	row := q.db.QueryRowContext(ctx, getPuzzleByArrayStr, arrayStr)
 	var i Puzzle
-	err := row.Scan(&i.ID, &i.ArrayStr, &i.CreatedAt)
+	err := row.Scan(
+		&i.ID,
+		&i.ArrayStr,
+		&i.CanonicalStr,
+		&i.Score,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPuzzleByCanonicalStr = `-- name: GetPuzzleByCanonicalStr :one
+SELECT id, array_str, canonical_str, score, created_at FROM puzzles
+WHERE canonical_str = $1 LIMIT 1
+`
+
+func (q *Queries) GetPuzzleByCanonicalStr(ctx context.Context, canonicalStr string) (Puzzle, error) {
+	row := q.db.QueryRowContext(ctx, getPuzzleByCanonicalStr, canonicalStr)
+	var i Puzzle
+	err := row.Scan(
+		&i.ID,
+		&i.ArrayStr,
+		&i.CanonicalStr,
+		&i.Score,
+		&i.CreatedAt,
+	)
 	return i, err
 }
 
 const getPuzzleByID = `-- name: GetPuzzleByID :one
-SELECT id, array_str, created_at FROM puzzles
+SELECT id, array_str, canonical_str, score, created_at FROM puzzles
 WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetPuzzleByID(ctx context.Context, id int64) (Puzzle, error) {
 	row := q.db.QueryRowContext(ctx, getPuzzleByID, id)
 	var i Puzzle
-	err := row.Scan(&i.ID, &i.ArrayStr, &i.CreatedAt)
+	err := row.Scan(
+		&i.ID,
+		&i.ArrayStr,
+		&i.CanonicalStr,
+		&i.Score,
+		&i.CreatedAt,
+	)
 	return i, err
 }
 
@@ -194,6 +236,71 @@ func (q *Queries) GetUserPuzzle(ctx context.Context, arg GetUserPuzzleParams) (U
 	return i, err
 }
 
+const importPuzzle = `-- name: ImportPuzzle :execrows
+INSERT INTO puzzles (
+	array_str, canonical_str, score
+) VALUES (
+	$1, $2, $3
+)
+ON CONFLICT DO NOTHING
+`
+
+type ImportPuzzleParams struct {
+	ArrayStr     string
+	CanonicalStr string
+	Score        int32
+}
+
+func (q *Queries) ImportPuzzle(ctx context.Context, arg ImportPuzzleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, importPuzzle, arg.ArrayStr, arg.CanonicalStr, arg.Score)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const listPuzzlesByScore = `-- name: ListPuzzlesByScore :many
+SELECT id, array_str, canonical_str, score, created_at FROM puzzles
+WHERE score BETWEEN $1 AND $2
+ORDER BY score, id
+LIMIT $3
+`
+
+type ListPuzzlesByScoreParams struct {
+	MinScore int32
+	MaxScore int32
+	RowLimit int32
+}
+
+func (q *Queries) ListPuzzlesByScore(ctx context.Context, arg ListPuzzlesByScoreParams) ([]Puzzle, error) {
+	rows, err := q.db.QueryContext(ctx, listPuzzlesByScore, arg.MinScore, arg.MaxScore, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Puzzle
+	for rows.Next() {
+		var i Puzzle
+		if err := rows.Scan(
+			&i.ID,
+			&i.ArrayStr,
+			&i.CanonicalStr,
+			&i.Score,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUserPuzzles = `-- name: ListUserPuzzles :many
 SELECT user_id, puzzle_id, created_at FROM user_puzzles
 WHERE user_id = $1
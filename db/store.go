@@ -43,3 +43,24 @@ func (s *Store) execTx(ctx context.Context, txFunc func(*Queries) error) error {
 	// Commit transaction
 	return tx.Commit()
 }
+
+// ImportPuzzles inserts puzzles as one atomic transaction, with context, skipping those with an
+// array_str or a canonical_str that is already stored, as either unique key conflicts. The number
+// of inserted puzzles is returned.
+func (s *Store) ImportPuzzles(ctx context.Context, puzzles []ImportPuzzleParams) (int, error) {
+	inserted := 0
+	err := s.execTx(ctx, func(q *Queries) error {
+		for _, puzzle := range puzzles {
+			n, err := q.ImportPuzzle(ctx, puzzle)
+			if err != nil {
+				return err
+			}
+			inserted += int(n)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return inserted, nil
+}
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/require"
+)
+
+// TestImportPuzzles imports random puzzles, repeating one of them and one already in the db, as one
+// transaction. The test fails if the duplicates are inserted or the new puzzles cannot be queried.
+func TestImportPuzzles(t *testing.T) {
+	store := NewStore(testDB)
+	existing := createRandomPuzzle(t)
+
+	var puzzles []ImportPuzzleParams
+	for i := 0; i < 5; i++ {
+		params := randomPuzzleParams(t, int32(gofakeit.Number(0, 5000)))
+		puzzles = append(puzzles, ImportPuzzleParams(params))
+	}
+	puzzles = append(puzzles,
+		puzzles[0],
+		ImportPuzzleParams{
+			ArrayStr:     existing.ArrayStr,
+			CanonicalStr: existing.CanonicalStr,
+			Score:        existing.Score,
+		},
+	)
+
+	inserted, err := store.ImportPuzzles(context.Background(), puzzles)
+	require.NoError(t, err)
+	require.Equal(t, 5, inserted)
+
+	for _, params := range puzzles[:5] {
+		puzzle, err := testQueries.GetPuzzleByArrayStr(context.Background(), params.ArrayStr)
+		require.NoError(t, err)
+		require.Equal(t, params.Score, puzzle.Score)
+	}
+
+	// Importing the same puzzles again inserts none of them.
+	inserted, err = store.ImportPuzzles(context.Background(), puzzles)
+	require.NoError(t, err)
+	require.Zero(t, inserted)
+}
@@ -3,7 +3,7 @@ module github.com/husseinelguindi/sudoku-api
 go 1.16
 
 require (
-	github.com/brianvoe/gofakeit/v6 v6.5.0 // indirect
-	github.com/lib/pq v1.10.2 // indirect
-	github.com/stretchr/testify v1.7.0 // indirect
+	github.com/brianvoe/gofakeit/v6 v6.5.0
+	github.com/lib/pq v1.10.2
+	github.com/stretchr/testify v1.7.0
 )
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/husseinelguindi/sudoku-api/db"
+	"github.com/husseinelguindi/sudoku-api/sudoku"
+
+	_ "github.com/lib/pq"
+)
+
+// defaultBatchSize is the number of puzzles inserted per transaction, by default.
+const defaultBatchSize = 1000
+
+// defaultPuzzleTimeout is the longest each search of a puzzle may take, by default.
+const defaultPuzzleTimeout = 5 * time.Second
+
+// errNotUnique is reported for puzzles without a unique solution, when uniqueness is required.
+var errNotUnique = errors.New("puzzle does not have a unique solution")
+
+// puzzleImporter represents a datastore that puzzles are imported into, such as db.Store.
+type puzzleImporter interface {
+	// ImportPuzzles inserts puzzles as one transaction, skipping duplicates of the line or the
+	// canonical form of a stored puzzle, and returns the number of inserted puzzles.
+	ImportPuzzles(ctx context.Context, puzzles []db.ImportPuzzleParams) (int, error)
+}
+
+// importOptions represents the configuration of an import.
+type importOptions struct {
+	batchSize   int           // The number of puzzles inserted per transaction
+	unique      bool          // Whether puzzles must have a unique solution to be valid
+	maxNodes    int           // The node budget of each search of a puzzle, unlimited if zero
+	maxDuration time.Duration // The duration budget of each search of a puzzle, unlimited if zero
+}
+
+// importSummary represents the number of puzzles of an import, by outcome. Duplicate counts the
+// puzzles skipped as their line or canonical form is already stored, and OverBudget counts the
+// invalid puzzles that could not be searched within the budget of the import.
+type importSummary struct {
+	Inserted   int
+	Duplicate  int
+	Invalid    int
+	OverBudget int
+}
+
+// String implements the Stringer interface for importSummary.
+func (s importSummary) String() string {
+	return fmt.Sprintf("inserted %d, duplicate line or canonical form %d, invalid %d (%d over budget)", s.Inserted, s.Duplicate,
+		s.Invalid, s.OverBudget)
+}
+
+// runImport runs the import command with args, importing a file of puzzles into the database.
+func runImport(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	dsn := flags.String("db", os.Getenv("DATABASE_URL"), "postgres connection string of the database")
+	batchSize := flags.Int("batch", defaultBatchSize, "number of puzzles inserted per transaction")
+	unique := flags.Bool("unique", false, "reject puzzles without a unique solution")
+	timeout := flags.Duration("timeout", defaultPuzzleTimeout, "longest each search of a puzzle may take, 0 for no limit")
+	maxNodes := flags.Int("max-nodes", 0, "most nodes each search of a puzzle may visit, 0 for no limit")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: %s import [flags] <file | ->\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 || *batchSize < 1 || *timeout < 0 || *maxNodes < 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	// Open the file of puzzles, or read them from stdin
+	in := os.Stdin
+	if path := flags.Arg(0); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			log.Fatalf("could not open file: %v", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	conn, err := sql.Open("postgres", *dsn)
+	if err != nil {
+		log.Fatalf("could not connect to db: %v", err)
+	}
+	defer conn.Close()
+
+	opts := importOptions{batchSize: *batchSize, unique: *unique, maxNodes: *maxNodes, maxDuration: *timeout}
+	summary, err := importPuzzles(context.Background(), in, db.NewStore(conn), opts, os.Stderr)
+	fmt.Println(summary)
+	if err != nil {
+		log.Fatalf("could not import puzzles: %v", err)
+	}
+}
+
+// importPuzzles streams puzzles from r, one per line in the line format of sudoku.Puzzle, and
+// inserts the valid ones into store in transactions of opts.batchSize puzzles. Blank lines and
+// lines starting with '#' are skipped. Invalid puzzles are reported to errLog by line number.
+//
+// A puzzle is invalid if it cannot be parsed, its values conflict, or it has no solution (or
+// more than one, if opts.unique is set). Puzzles are searched within the budget of opts, and
+// puzzles exceeding it are invalid as well, counted apart in the summary. Valid puzzles are stored in their canonical line
+// format, scored by their rating, such that the same puzzle written differently is a duplicate.
+// Puzzles equivalent to a stored puzzle (see sudoku.Puzzle.Canonical) are duplicates as well.
+// The summary of the puzzles processed so far is returned with any error.
+func importPuzzles(ctx context.Context, r io.Reader, store puzzleImporter, opts importOptions, errLog io.Writer) (importSummary, error) {
+	var summary importSummary
+	batch := make([]db.ImportPuzzleParams, 0, opts.batchSize)
+
+	// flush inserts the pending batch.
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		inserted, err := store.ImportPuzzles(ctx, batch)
+		if err != nil {
+			return err
+		}
+		summary.Inserted += inserted
+		summary.Duplicate += len(batch) - inserted
+		batch = batch[:0]
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		params, err := importParams(ctx, line, opts)
+		if err != nil && ctx.Err() != nil {
+			return summary, ctx.Err()
+		}
+		if err != nil {
+			summary.Invalid++
+			if errors.Is(err, sudoku.ErrBudgetExceeded) {
+				summary.OverBudget++
+			}
+			fmt.Fprintf(errLog, "line %d: %v\n", n, err)
+			continue
+		}
+
+		batch = append(batch, params)
+		if len(batch) == opts.batchSize {
+			if err := flush(); err != nil {
+				return summary, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return summary, err
+	}
+	return summary, flush()
+}
+
+// importParams returns the params to insert the puzzle of line with, or an error if the puzzle
+// is invalid (see importPuzzles).
+func importParams(ctx context.Context, line string, opts importOptions) (db.ImportPuzzleParams, error) {
+	puzzle, err := sudoku.ParseLine(line, sudoku.WithMaxNodes(opts.maxNodes), sudoku.WithMaxDuration(opts.maxDuration))
+	if err != nil {
+		return db.ImportPuzzleParams{}, err
+	}
+	if conflicts := puzzle.Validate(); len(conflicts) != 0 {
+		return db.ImportPuzzleParams{}, fmt.Errorf("%d conflicting values", len(conflicts))
+	}
+	if opts.unique {
+		res, err := puzzle.CountContext(ctx, 2)
+		if err != nil {
+			return db.ImportPuzzleParams{}, err
+		}
+		if res.Solutions != 1 {
+			return db.ImportPuzzleParams{}, errNotUnique
+		}
+	}
+
+	// Rate also ensures the puzzle has a solution, within the budget
+	rating, err := puzzle.Rate()
+	if err != nil {
+		return db.ImportPuzzleParams{}, err
+	}
+	text, err := puzzle.MarshalText()
+	if err != nil {
+		return db.ImportPuzzleParams{}, err
+	}
+
+	// Puzzles too large to canonicalize, or with variant constraints such as Killer cages, are
+	// their own canonical form
+	canonical, err := puzzle.Canonical()
+	if errors.Is(err, sudoku.ErrCanonicalSize) || errors.Is(err, sudoku.ErrCanonicalVariant) {
+		canonical = puzzle
+	} else if err != nil {
+		return db.ImportPuzzleParams{}, err
+	}
+	canonicalText, err := canonical.MarshalText()
+	if err != nil {
+		return db.ImportPuzzleParams{}, err
+	}
+
+	return db.ImportPuzzleParams{
+		ArrayStr:     string(text),
+		CanonicalStr: string(canonicalText),
+		Score:        int32(rating.Score),
+	}, nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/husseinelguindi/sudoku-api/db"
+	"github.com/husseinelguindi/sudoku-api/sudoku"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryImporter is a puzzleImporter that stores puzzles in memory, by their canonical line,
+// recording the size of each batch. Batches fail with err, if it is set.
+type memoryImporter struct {
+	puzzles map[string]string
+	batches []int
+	err     error
+}
+
+func (m *memoryImporter) ImportPuzzles(ctx context.Context, puzzles []db.ImportPuzzleParams) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	if m.puzzles == nil {
+		m.puzzles = make(map[string]string)
+	}
+	m.batches = append(m.batches, len(puzzles))
+	inserted := 0
+	for _, p := range puzzles {
+		if _, ok := m.puzzles[p.CanonicalStr]; !ok {
+			m.puzzles[p.CanonicalStr] = p.ArrayStr
+			inserted++
+		}
+	}
+	return inserted, nil
+}
+
+const (
+	// importPuzzle has a unique solution.
+	importPuzzle = "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
+	// importTransposed is importPuzzle, transposed.
+	importTransposed = "56.847...3.9...6....8.......1..8..4.79.6.2.18.5..3..9.......2....6...8.7...316.59"
+	// importAmbiguous has two solutions, swapping the 4s and 5s of its seventh and eighth rows.
+	importAmbiguous = "534678912672195348198342567859761423426853791713924856961.3728.287.1963.345286179"
+	// importConflict has two 5s in its first row.
+	importConflict = "55..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
+)
+
+func TestImportPuzzles(t *testing.T) {
+	input := strings.Join([]string{
+		"# Puzzles to import",
+		importPuzzle,
+		"",
+		importAmbiguous,
+		strings.ReplaceAll(importPuzzle, ".", "0"), // The same puzzle, written differently
+		importTransposed,
+		importConflict,
+		"not a puzzle",
+	}, "\n")
+
+	t.Run("all", func(t *testing.T) {
+		store := &memoryImporter{}
+		errLog := &bytes.Buffer{}
+		summary, err := importPuzzles(context.Background(), strings.NewReader(input), store,
+			importOptions{batchSize: 2}, errLog)
+		require.NoError(t, err)
+		require.Equal(t, importSummary{Inserted: 2, Duplicate: 2, Invalid: 2}, summary)
+		require.Equal(t, []int{2, 2}, store.batches)
+		require.Len(t, store.puzzles, 2)
+		for _, line := range []string{importPuzzle, importAmbiguous} {
+			puzzle, err := sudoku.ParseLine(line)
+			require.NoError(t, err)
+			canonical, err := puzzle.Canonical()
+			require.NoError(t, err)
+			text, err := canonical.MarshalText()
+			require.NoError(t, err)
+			require.Equal(t, line, store.puzzles[string(text)])
+		}
+		require.Contains(t, errLog.String(), "line 7: ")
+		require.Contains(t, errLog.String(), "line 8: ")
+	})
+
+	t.Run("unique", func(t *testing.T) {
+		store := &memoryImporter{}
+		errLog := &bytes.Buffer{}
+		summary, err := importPuzzles(context.Background(), strings.NewReader(input), store,
+			importOptions{batchSize: 10, unique: true}, errLog)
+		require.NoError(t, err)
+		require.Equal(t, importSummary{Inserted: 1, Duplicate: 2, Invalid: 3}, summary)
+		require.Equal(t, []int{3}, store.batches)
+		require.Contains(t, errLog.String(), "line 4: "+errNotUnique.Error())
+	})
+
+	t.Run("killer", func(t *testing.T) {
+		// Killer puzzles cannot be canonicalized, so they are their own canonical form.
+		killer := "................;2+6+10=6;5+9=5;0+4=4;1=2;3+7=7;11+13+14+15=10;8+12=6"
+		store := &memoryImporter{}
+		summary, err := importPuzzles(context.Background(), strings.NewReader(killer), store,
+			importOptions{batchSize: 10, unique: true}, &bytes.Buffer{})
+		require.NoError(t, err)
+		require.Equal(t, importSummary{Inserted: 1}, summary)
+		require.Equal(t, map[string]string{killer: killer}, store.puzzles)
+	})
+
+	t.Run("budget", func(t *testing.T) {
+		// Row 8 already holds the 9 that column 8 leaves for its last position, which backtracking
+		// only finds out after exhausting the rest of the grid.
+		unsolvable := "........1........2........3........4........5........6........7........89........"
+		errLog := &bytes.Buffer{}
+		summary, err := importPuzzles(context.Background(), strings.NewReader(unsolvable+"\n"+importPuzzle),
+			&memoryImporter{}, importOptions{batchSize: 10, unique: true, maxNodes: 100000}, errLog)
+		require.NoError(t, err)
+		require.Equal(t, importSummary{Inserted: 1, Invalid: 1, OverBudget: 1}, summary)
+		require.Contains(t, errLog.String(), "line 1: "+sudoku.ErrBudgetExceeded.Error())
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		storeErr := errors.New("connection refused")
+		store := &memoryImporter{err: storeErr}
+		_, err := importPuzzles(context.Background(), strings.NewReader(input), store,
+			importOptions{batchSize: 1}, &bytes.Buffer{})
+		require.ErrorIs(t, err, storeErr)
+	})
+}
@@ -1,5 +1,35 @@
 package main
 
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/husseinelguindi/sudoku-api/api"
+)
+
+// main serves the API, or runs the import command when invoked as "sudoku-api import".
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		runImport(os.Args[2:])
+		return
+	}
+
+	addr := flag.String("addr", ":8080", "address for the HTTP server to listen on")
+	flag.Parse()
+
+	server := &http.Server{
+		Addr:         *addr,
+		Handler:      api.NewServer(),
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  time.Minute,
+	}
 
+	log.Printf("listening on %s", *addr)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatalf("could not start server: %v", err)
+	}
 }
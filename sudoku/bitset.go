@@ -2,27 +2,108 @@ package sudoku
 
 import (
 	"fmt"
-	"math/big"
+	"math/bits"
 	"strings"
 )
 
+// wordBits is the number of bits in a word of a bitSet.
+const wordBits = 64
+
+// bitSet represents a set of bits backed by machine words. The first word is stored inline, so
+// bits below 64 (the values of puzzles up to 63x63) never allocate. Higher bits fall back to
+// additional words, allocated as needed.
 type bitSet struct {
-	s big.Int
+	w    uint64   // Bits [0, 64)
+	more []uint64 // Bits [64, ...), one word per 64 bits
 }
 
+// Set sets the bit at index i to b (0 or 1).
 func (bs *bitSet) Set(i int, b uint) {
-	bs.s.SetBit(&bs.s, i, b)
+	word, mask := bs.word(i, b != 0)
+	if word == nil {
+		return
+	}
+	if b == 0 {
+		*word &^= mask
+	} else {
+		*word |= mask
+	}
+}
+
+// word returns the word holding the bit at index i, and the mask of the bit in that word. If the
+// word is not allocated, it is allocated when grow is true, otherwise word is nil.
+func (bs *bitSet) word(i int, grow bool) (word *uint64, mask uint64) {
+	mask = 1 << uint(i%wordBits)
+	if i < wordBits {
+		return &bs.w, mask
+	}
+	n := i/wordBits - 1
+	if n >= len(bs.more) {
+		if !grow {
+			return nil, mask
+		}
+		bs.more = append(bs.more, make([]uint64, n+1-len(bs.more))...)
+	}
+	return &bs.more[n], mask
 }
+
+// Reset clears all bits, keeping the allocated words.
 func (bs *bitSet) Reset() {
-	bs.s.SetInt64(0)
+	bs.w = 0
+	for i := range bs.more {
+		bs.more[i] = 0
+	}
+}
+
+// Copy returns a copy of the bitset that does not share its underlying storage.
+func (bs *bitSet) Copy() bitSet {
+	c := bitSet{w: bs.w}
+	if len(bs.more) != 0 {
+		c.more = append([]uint64(nil), bs.more...)
+	}
+	return c
 }
 
+// Get returns the bit at index i.
 func (bs *bitSet) Get(i int) uint {
-	return bs.s.Bit(i)
+	if i < wordBits {
+		return uint(bs.w>>uint(i)) & 1
+	}
+	n := i/wordBits - 1
+	if n >= len(bs.more) {
+		return 0
+	}
+	return uint(bs.more[n]>>uint(i%wordBits)) & 1
 }
 
+// Len returns the length of the bitset, the index of the highest set bit plus one.
 func (bs *bitSet) Len() int {
-	return bs.s.BitLen()
+	for n := len(bs.more) - 1; n >= 0; n-- {
+		if bs.more[n] != 0 {
+			return (n+1)*wordBits + bits.Len64(bs.more[n])
+		}
+	}
+	return bits.Len64(bs.w)
+}
+
+// Count returns the number of set bits.
+func (bs *bitSet) Count() int {
+	n := bits.OnesCount64(bs.w)
+	for _, w := range bs.more {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// Union sets the bits of other in the bitset.
+func (bs *bitSet) Union(other *bitSet) {
+	bs.w |= other.w
+	if len(other.more) > len(bs.more) {
+		bs.more = append(bs.more, make([]uint64, len(other.more)-len(bs.more))...)
+	}
+	for n, w := range other.more {
+		bs.more[n] |= w
+	}
 }
 
 func (bs *bitSet) String() string {
@@ -34,3 +115,12 @@ func (bs *bitSet) String() string {
 	sb.WriteByte('}')
 	return sb.String()
 }
+
+// cloneBitSets returns a copy of each bitset in sets.
+func cloneBitSets(sets []bitSet) []bitSet {
+	c := make([]bitSet, len(sets))
+	for i := range sets {
+		c[i] = sets[i].Copy()
+	}
+	return c
+}
@@ -0,0 +1,71 @@
+package sudoku
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBitSet(t *testing.T) {
+	// Indices within the inline word, and the words allocated on demand.
+	for _, indices := range [][]int{{0, 5, 63}, {1, 64, 130, 625}} {
+		var bs bitSet
+		require.Zero(t, bs.Len())
+
+		for _, i := range indices {
+			bs.Set(i, 1)
+		}
+		require.Equal(t, indices[len(indices)-1]+1, bs.Len())
+		require.Equal(t, len(indices), bs.Count())
+		for _, i := range indices {
+			require.Equal(t, uint(1), bs.Get(i))
+			require.Zero(t, bs.Get(i+1))
+		}
+
+		// Copies do not share storage.
+		c := bs.Copy()
+		c.Set(indices[0], 0)
+		require.Equal(t, uint(1), bs.Get(indices[0]))
+		require.Zero(t, c.Get(indices[0]))
+
+		// Unsetting the highest bit shrinks the length.
+		bs.Set(indices[len(indices)-1], 0)
+		require.Equal(t, indices[len(indices)-2]+1, bs.Len())
+
+		bs.Reset()
+		require.Zero(t, bs.Len())
+		require.Zero(t, bs.Count())
+	}
+}
+
+func TestBitSetUnion(t *testing.T) {
+	var a, b bitSet
+	a.Set(3, 1)
+	b.Set(4, 1)
+	b.Set(200, 1)
+
+	a.Union(&b)
+	require.Equal(t, 3, a.Count())
+	require.Equal(t, 201, a.Len())
+	require.Equal(t, uint(1), a.Get(3))
+	require.Equal(t, uint(1), a.Get(200))
+	require.Equal(t, 2, b.Count())
+}
+
+func BenchmarkBitSet(b *testing.B) {
+	for _, side := range []int{9, 25, 100} {
+		b.Run(fmt.Sprintf("%d", side), func(b *testing.B) {
+			b.ReportAllocs()
+			var bs bitSet
+			for i := 0; i < b.N; i++ {
+				val := i%side + 1
+				bs.Set(val, 1)
+				if bs.Get(val) != 1 {
+					b.Fatal("bit was not set")
+				}
+				bs.Set(val, 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package sudoku
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Cage represents a cage of a Killer Sudoku puzzle: a group of cells whose values must add up to
+// Sum, where a value may only appear once (see WithCages).
+type Cage struct {
+	Sum   int    `json:"sum"`
+	Cells []Cell `json:"cells"`
+}
+
+// ErrCage is returned when a cage has no cells, more cells than the puzzle side, cells out of
+// the puzzle bounds or in another cage, or a sum its cells cannot add up to.
+var ErrCage = errors.New("malformed cage")
+
+// cageConstraint is the constraint of the cages of a puzzle, a unit per cage. It is a pointer,
+// as its cages cannot be compared.
+type cageConstraint struct {
+	cages []Cage
+}
+
+func (*cageConstraint) Type() UnitType { return UnitCage }
+
+func (c *cageConstraint) Units(p Puzzle) [][]Cell {
+	units := make([][]Cell, len(c.cages))
+	for i, cage := range c.cages {
+		units[i] = cage.Cells
+	}
+	return units
+}
+
+// Cages returns the cages of the puzzle, in the order they were added (see WithCages).
+func (p Puzzle) Cages() []Cage {
+	return append([]Cage(nil), p.cages...)
+}
+
+// buildCages indexes the cages of the puzzle by cell, and adds their constraint after every
+// other constraint. An error wrapping ErrCage is returned if a cage is malformed.
+func (p *Puzzle) buildCages() error {
+	if len(p.cages) == 0 {
+		return nil
+	}
+	side := len(p.Arr)
+	p.cellCages = make([]int, side*side)
+	for i := range p.cellCages {
+		p.cellCages[i] = -1
+	}
+	for i, cage := range p.cages {
+		if len(cage.Cells) == 0 || len(cage.Cells) > side {
+			return fmt.Errorf("%w: cage %d has %d cells", ErrCage, i, len(cage.Cells))
+		}
+		for _, cell := range cage.Cells {
+			if int(cell.Row) >= side || int(cell.Col) >= side {
+				return fmt.Errorf("%w: cage %d has cell at row %d, column %d", ErrCage, i, cell.Row, cell.Col)
+			}
+			j := p.cellIndex(cell.Row, cell.Col)
+			if p.cellCages[j] != -1 {
+				return fmt.Errorf("%w: row %d, column %d is in cages %d and %d", ErrCage, cell.Row, cell.Col,
+					p.cellCages[j], i)
+			}
+			p.cellCages[j] = i
+		}
+		if lo, hi := sumRange(len(cage.Cells), side); cage.Sum < lo || cage.Sum > hi {
+			return fmt.Errorf("%w: cage %d of %d cells cannot add up to %d", ErrCage, i, len(cage.Cells),
+				cage.Sum)
+		}
+	}
+	p.constraints = append(p.constraints, &cageConstraint{p.cages})
+	return nil
+}
+
+// sumRange returns the smallest and largest sums of n distinct values of a puzzle side.
+func sumRange(n, side int) (lo, hi int) {
+	for i := 0; i < n; i++ {
+		lo += 1 + i
+		hi += side - i
+	}
+	return lo, hi
+}
+
+// cageTaken adds to bs the values that cannot be placed at the vacant row and col position
+// without leaving its cage unable to add up to its sum, given the values already in the cage.
+// bs is left untouched for positions outside of cages.
+func (p Puzzle) cageTaken(row, col PuzzleInt, bs *bitSet) {
+	if p.cellCages == nil {
+		return
+	}
+	c := p.cellCages[p.cellIndex(row, col)]
+	if c == -1 {
+		return
+	}
+	cage := p.cages[c]
+	rest, vacant := cage.Sum, -1 // The position itself is not counted
+	for _, cell := range cage.Cells {
+		val := p.Arr[cell.Row][cell.Col]
+		rest -= int(val)
+		if val == 0 {
+			vacant++
+		}
+	}
+	if vacant < 0 {
+		return
+	}
+
+	// The values not in the cage, in ascending order, and their prefix sums. The cage constraint
+	// is the last one, so its units are the last ones.
+	used := &p.unitVals[len(p.units)-len(p.cages)+c]
+	var freeBuf, prefixBuf [65]int
+	free, prefix := freeBuf[:0], append(prefixBuf[:0], 0)
+	for val := 1; val <= len(p.Arr); val++ {
+		if used.Get(val) == 0 {
+			free = append(free, val)
+			prefix = append(prefix, prefix[len(prefix)-1]+val)
+		}
+	}
+
+	n := len(free)
+	for i, val := range free {
+		// The smallest and largest sums of the other vacant positions, with values other than val.
+		lo, hi := prefix[vacant], prefix[n]-prefix[n-vacant]
+		if i < vacant {
+			lo += free[vacant] - val
+		}
+		if i >= n-vacant {
+			hi += free[n-vacant-1] - val
+		}
+		if rest-val < lo || rest-val > hi {
+			bs.Set(val, 1)
+		}
+	}
+}
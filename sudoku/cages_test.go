@@ -0,0 +1,126 @@
+package sudoku
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCages(t *testing.T) {
+	testCases := []struct {
+		name     string
+		line     string
+		solution string
+	}{
+		{
+			name:     "4x4",
+			line:     "................;2+6+10=6;5+9=5;0+4=4;1=2;3+7=7;11+13+14+15=10;8+12=6",
+			solution: "3214142341322341;2+6+10=6;5+9=5;0+4=4;1=2;3+7=7;11+13+14+15=10;8+12=6",
+		},
+		{
+			name: "6x6",
+			line: "2x3:....................................;0+1=3;14+15=7;2+3+9=10;4+5+10+16=15;6+7=11;" +
+				"34+35=5;11+17=10;8=3;12+18=5;26+27+33=12;13+19+25=11;20+21+22=14;23+29=6;24+30+31+32=13;28=1",
+			solution: "2x3:214563653124135246426351362415541632;0+1=3;14+15=7;2+3+9=10;4+5+10+16=15;6+7=11;" +
+				"34+35=5;11+17=10;8=3;12+18=5;26+27+33=12;13+19+25=11;20+21+22=14;23+29=6;24+30+31+32=13;28=1",
+		},
+		{
+			name: "9x9",
+			line: strings.Repeat(".", 81) + ";0+9+10+18=21;23+24+33+42=23;32+41+50=21;1+2=6;44+51+52+53=12;" +
+				"3+12=15;39+47+48+49=21;4+13=7;5+14+15+16=21;65+66=7;26+35=13;6+7=6;8+17=12;11+20+28+29=15;" +
+				"67+68+77=13;19=8;36+37+38=19;61+62+71=13;21+22+30=15;69+70=12;25+34+43=13;63+72+73=15;" +
+				"74+75=8;27=5;55+64=9;31+40=7;57+58+59=16;78+79=14;45+46=10;54=7;56=8;60=4;80=2;76=7",
+			solution: "942763158653812794187495326514236987829147635376958241768529413231684579495371862" +
+				";0+9+10+18=21;23+24+33+42=23;32+41+50=21;1+2=6;44+51+52+53=12;" +
+				"3+12=15;39+47+48+49=21;4+13=7;5+14+15+16=21;65+66=7;26+35=13;6+7=6;8+17=12;11+20+28+29=15;" +
+				"67+68+77=13;19=8;36+37+38=19;61+62+71=13;21+22+30=15;69+70=12;25+34+43=13;63+72+73=15;" +
+				"74+75=8;27=5;55+64=9;31+40=7;57+58+59=16;78+79=14;45+46=10;54=7;56=8;60=4;80=2;76=7",
+		},
+	}
+	for _, tc := range testCases {
+		for _, engine := range engines {
+			t.Run(tc.name+"/"+engine.name, func(t *testing.T) {
+				puzzle, err := ParseLine(tc.line, engine.opts...)
+				require.NoError(t, err)
+				require.True(t, puzzle.HasUniqueSolution())
+
+				solution, ok := puzzle.Solved()
+				require.True(t, ok)
+				text, err := solution.MarshalText()
+				require.NoError(t, err)
+				require.Equal(t, tc.solution, string(text))
+				require.Empty(t, solution.Validate())
+			})
+		}
+	}
+}
+
+func TestCageCandidates(t *testing.T) {
+	puzzle, err := NewPuzzle(emptyArr(4), WithCages(
+		Cage{Sum: 3, Cells: []Cell{{0, 0}, {0, 1}}},
+		Cage{Sum: 7, Cells: []Cell{{1, 0}, {1, 1}}},
+		Cage{Sum: 6, Cells: []Cell{{2, 0}, {2, 1}, {3, 0}}},
+	))
+	require.NoError(t, err)
+	require.Equal(t, []PuzzleInt{1, 2}, puzzle.Candidates(0, 0))
+	require.Equal(t, []PuzzleInt{3, 4}, puzzle.Candidates(1, 1))
+	require.Equal(t, []PuzzleInt{1, 2, 3}, puzzle.Candidates(3, 0))
+	// Positions outside of cages are left to their units.
+	require.Equal(t, []PuzzleInt{1, 2, 3, 4}, puzzle.Candidates(3, 3))
+
+	// The remaining sum of a cage narrows the candidates of its vacant positions.
+	puzzle.Arr[2][0] = 3
+	puzzle.populate()
+	require.Equal(t, []PuzzleInt{1, 2}, puzzle.Candidates(2, 1))
+	require.False(t, puzzle.isValidPos(3, 0, 3))
+	require.True(t, puzzle.isValidPos(3, 0, 1))
+}
+
+func TestCageValidate(t *testing.T) {
+	arr := emptyArr(4)
+	arr[0][0], arr[0][1] = 4, 3 // Exceeding a cage of 6
+	arr[1][0], arr[2][0] = 1, 2 // Falling short of a full cage of 4
+	arr[1][2], arr[2][2] = 3, 3 // Repeating 3 in a cage of 6
+	puzzle, err := NewPuzzle(arr, WithCages(
+		Cage{Sum: 6, Cells: []Cell{{0, 0}, {0, 1}, {0, 2}}},
+		Cage{Sum: 4, Cells: []Cell{{1, 0}, {2, 0}}},
+		Cage{Sum: 6, Cells: []Cell{{1, 2}, {2, 2}}},
+	))
+	require.NoError(t, err)
+	require.Equal(t, []Conflict{
+		{Unit: UnitColumn, Value: 3, A: Cell{1, 2}, B: Cell{2, 2}},
+		{Unit: UnitCage, Value: 3, A: Cell{1, 2}, B: Cell{2, 2}},
+		{Unit: UnitCage, A: Cell{0, 0}, B: Cell{0, 1}, Sum: 7},
+		{Unit: UnitCage, A: Cell{1, 0}, B: Cell{2, 0}, Sum: 3},
+	}, puzzle.Validate())
+	require.False(t, puzzle.Solve())
+}
+
+func TestCageErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		cages []Cage
+	}{
+		{"no cells", []Cage{{Sum: 1}}},
+		{"too many cells", []Cage{{Sum: 10, Cells: []Cell{{0, 0}, {0, 1}, {0, 2}, {0, 3}, {1, 0}}}}},
+		{"out of bounds", []Cage{{Sum: 3, Cells: []Cell{{0, 0}, {0, 4}}}}},
+		{"repeated cell", []Cage{{Sum: 3, Cells: []Cell{{0, 0}, {0, 0}}}}},
+		{"overlap", []Cage{{Sum: 3, Cells: []Cell{{0, 0}, {0, 1}}}, {Sum: 3, Cells: []Cell{{0, 1}, {1, 1}}}}},
+		{"sum too small", []Cage{{Sum: 2, Cells: []Cell{{0, 0}, {0, 1}}}}},
+		{"sum too large", []Cage{{Sum: 8, Cells: []Cell{{0, 0}, {0, 1}}}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewPuzzle(emptyArr(4), WithCages(tc.cages...))
+			require.ErrorIs(t, err, ErrCage)
+		})
+	}
+}
+
+func TestCagesCanonical(t *testing.T) {
+	puzzle, err := NewPuzzle(emptyArr(4), WithCages(Cage{Sum: 3, Cells: []Cell{{0, 0}, {0, 1}}}))
+	require.NoError(t, err)
+	_, err = puzzle.Canonical()
+	require.ErrorIs(t, err, ErrCanonicalVariant)
+}
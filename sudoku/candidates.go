@@ -0,0 +1,62 @@
+package sudoku
+
+import "encoding/json"
+
+// CandidateGrid represents the candidates (pencil marks) of every cell of a puzzle, indexed by
+// row and column. Occupied cells have no candidates.
+type CandidateGrid [][][]PuzzleInt
+
+// candidates returns the bitset of the values that can be placed at the vacant row and col
+// position without breaking the constraints of the puzzle.
+func (p Puzzle) candidates(row, col PuzzleInt) bitSet {
+	var bs bitSet
+	if p.Arr[row][col] != 0 {
+		return bs
+	}
+	taken := p.taken(row, col)
+	for val := PuzzleInt(1); int(val) <= len(p.Arr); val++ {
+		if taken.Get(int(val)) == 0 {
+			bs.Set(int(val), 1)
+		}
+	}
+	return bs
+}
+
+// Candidates returns the values, in ascending order, that can be placed at the row and col
+// position without breaking the constraints of the puzzle. Occupied and out of bounds
+// positions have no candidates.
+func (p Puzzle) Candidates(row, col PuzzleInt) []PuzzleInt {
+	vals := []PuzzleInt{}
+	if int(row) >= len(p.Arr) || int(col) >= len(p.Arr) {
+		return vals
+	}
+	bs := p.candidates(row, col)
+	for val := PuzzleInt(1); int(val) <= len(p.Arr); val++ {
+		if bs.Get(int(val)) == 1 {
+			vals = append(vals, val)
+		}
+	}
+	return vals
+}
+
+// CandidateGrid returns the candidates of every cell of the puzzle (see Candidates).
+func (p Puzzle) CandidateGrid() CandidateGrid {
+	grid := make(CandidateGrid, len(p.Arr))
+	for row := range grid {
+		grid[row] = make([][]PuzzleInt, len(p.Arr[row]))
+		for col := range grid[row] {
+			grid[row][col] = p.Candidates(PuzzleInt(row), PuzzleInt(col))
+		}
+	}
+	return grid
+}
+
+// String implements the Stringer interface for CandidateGrid by encoding into JSON, like
+// Puzzle.String.
+func (g CandidateGrid) String() string {
+	b, err := json.Marshal(g)
+	if err != nil {
+		panic("could not encode candidate grid into json")
+	}
+	return string(b)
+}
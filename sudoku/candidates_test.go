@@ -0,0 +1,65 @@
+package sudoku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCandidates(t *testing.T) {
+	type test struct {
+		row, col PuzzleInt
+		expected []PuzzleInt
+	}
+	testCases := []struct {
+		arr        [][]PuzzleInt
+		puzzleOpts []PuzzleOption
+		tests      []test
+	}{
+		{
+			arr: [][]PuzzleInt{
+				{5, 1, 0, 0, 2, 0},
+				{0, 0, 4, 0, 0, 0},
+				{0, 0, 2, 0, 0, 0},
+				{0, 0, 0, 0, 6, 5},
+				{0, 0, 5, 0, 0, 0},
+				{0, 0, 0, 0, 1, 3},
+			},
+			puzzleOpts: []PuzzleOption{WithBoxDimensions(2, 3)},
+			tests: []test{
+				{0, 0, []PuzzleInt{}}, // Occupied
+				{0, 2, []PuzzleInt{3, 6}},
+				{3, 0, []PuzzleInt{1, 3, 4}},
+				{1, 5, []PuzzleInt{1, 6}},
+				{5, 5, []PuzzleInt{}}, // Occupied
+				{6, 0, []PuzzleInt{}}, // Out of bounds
+			},
+		},
+	}
+	for _, tc := range testCases {
+		puzzle, err := NewPuzzle(tc.arr, tc.puzzleOpts...)
+		require.NoError(t, err)
+		for _, test := range tc.tests {
+			require.Equal(t, test.expected, puzzle.Candidates(test.row, test.col))
+		}
+	}
+}
+
+func TestCandidateGrid(t *testing.T) {
+	puzzle, err := NewPuzzle([][]PuzzleInt{
+		{1, 0, 0, 0},
+		{0, 0, 0, 2},
+		{0, 0, 3, 0},
+		{0, 4, 0, 0},
+	})
+	require.NoError(t, err)
+
+	grid := puzzle.CandidateGrid()
+	require.Equal(t, CandidateGrid{
+		{{}, {2, 3}, {4}, {3, 4}},
+		{{3, 4}, {3}, {1, 4}, {}},
+		{{2}, {1, 2}, {}, {1, 4}},
+		{{2, 3}, {}, {1, 2}, {1}},
+	}, grid)
+	require.Equal(t, "[[[],[2,3],[4],[3,4]],[[3,4],[3],[1,4],[]],[[2],[1,2],[],[1,4]],[[2,3],[],[1,2],[1]]]", grid.String())
+}
@@ -0,0 +1,266 @@
+package sudoku
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// maxCanonicalSide is the side length of the largest puzzles that can be canonicalized, as the
+// number of equivalent puzzles grows factorially with the side length.
+const maxCanonicalSide = 9
+
+// Errors returned when a puzzle cannot be canonicalized.
+var (
+	ErrCanonicalSize    = errors.New("puzzle is too large to canonicalize")
+	ErrCanonicalVariant = errors.New("puzzle with variant constraints cannot be canonicalized")
+)
+
+// Canonical returns the minimal representative of the equivalence class of the puzzle. Puzzles
+// are equivalent if one can be turned into the other by relabelling digits, permuting rows
+// within a band, bands, columns within a stack, stacks, or transposing square boxes, all of which
+// preserve the solutions of a puzzle. Equivalent puzzles have identical canonical forms.
+//
+// The representative is the equivalent puzzle that is lexicographically smallest in row-major
+// order, with empty positions ordered before digits, and digits relabelled in their order of
+// first appearance. Canonical returns ErrCanonicalSize for puzzles with a side larger than 9,
+// and ErrCanonicalVariant for puzzles with constraints other than rows, columns, and boxes (see
+// WithConstraints), which the symmetries may break. The puzzle is left untouched.
+func (p Puzzle) Canonical() (Puzzle, error) {
+	side := len(p.Arr)
+	if side > maxCanonicalSide {
+		return Puzzle{}, fmt.Errorf("%w: side %d is larger than %d", ErrCanonicalSize, side, maxCanonicalSide)
+	}
+	if !p.standard() {
+		return Puzzle{}, ErrCanonicalVariant
+	}
+
+	c := canonicalizer{
+		side:  side,
+		h:     int(p.boxHeight),
+		w:     int(p.boxWidth),
+		best:  make([]PuzzleInt, side*side),
+		cur:   make([]PuzzleInt, side*side),
+		first: true,
+	}
+	c.orient(p.Arr)
+	if c.h == c.w {
+		// Transposing is only a symmetry of puzzles with square boxes.
+		transposed := make([][]PuzzleInt, side)
+		for i := range transposed {
+			transposed[i] = make([]PuzzleInt, side)
+			for j := range transposed[i] {
+				transposed[i][j] = p.Arr[j][i]
+			}
+		}
+		c.orient(transposed)
+	}
+
+	arr := make([][]PuzzleInt, side)
+	for i := range arr {
+		arr[i] = c.best[i*side : (i+1)*side : (i+1)*side]
+	}
+	return p.withArr(arr), nil
+}
+
+// canonicalizer represents the search for the canonical form of a puzzle. Column orders are
+// enumerated exhaustively, and rows are then placed by branch and bound, keeping only the
+// smallest rows at each position and pruning orders that compare above the best found so far.
+type canonicalizer struct {
+	side, h, w int
+	grid       [][]PuzzleInt // The orientation of the puzzle being searched
+	cols       []int         // The column order being searched
+	bands      []int         // The bands of the rows placed, in order
+
+	best  []PuzzleInt // The smallest relabelled matrix found, row-major
+	cur   []PuzzleInt // The relabelled matrix being built, row-major
+	first bool        // Whether best is unset
+}
+
+// orient searches the column orders of grid, an orientation of the puzzle.
+func (c *canonicalizer) orient(grid [][]PuzzleInt) {
+	c.grid = grid
+	c.cols = make([]int, 0, c.side)
+	c.permuteStacks(make([]bool, c.side/c.w))
+}
+
+// permuteStacks appends the columns of each unused stack to the column order, in turn. Stacks
+// with identical columns are interchangeable, so only the first of them is tried.
+func (c *canonicalizer) permuteStacks(used []bool) {
+	if len(c.cols) == c.side {
+		c.placeRows(0, make([]PuzzleInt, c.side+1), 1, make([]bool, c.side))
+		return
+	}
+	tried := make(map[string]bool)
+	for stack := range used {
+		if used[stack] {
+			continue
+		}
+		key := c.stackKey(stack)
+		if tried[key] {
+			continue
+		}
+		tried[key] = true
+		used[stack] = true
+		c.permuteColumns(stack, 0, make([]bool, c.w), used)
+		used[stack] = false
+	}
+}
+
+// permuteColumns appends the unused columns of stack to the column order, in turn, moving on to
+// the next stack once all are placed. Identical columns are interchangeable, so only the first
+// of them is tried.
+func (c *canonicalizer) permuteColumns(stack, placed int, used []bool, stacks []bool) {
+	if placed == c.w {
+		c.permuteStacks(stacks)
+		return
+	}
+	tried := make(map[string]bool)
+	for i := range used {
+		col := stack*c.w + i
+		key := c.columnKey(col)
+		if used[i] || tried[key] {
+			continue
+		}
+		tried[key] = true
+		used[i] = true
+		c.cols = append(c.cols, col)
+		c.permuteColumns(stack, placed+1, used, stacks)
+		c.cols = c.cols[:len(c.cols)-1]
+		used[i] = false
+	}
+}
+
+// columnKey returns the values of column col of the grid as a string.
+func (c *canonicalizer) columnKey(col int) string {
+	key := make([]byte, c.side)
+	for row := range key {
+		key[row] = byte(c.grid[row][col])
+	}
+	return string(key)
+}
+
+// repeatsRow returns whether row row of the grid is identical to one of rows in the same band.
+func (c *canonicalizer) repeatsRow(row int, rows []int) bool {
+	for _, other := range rows {
+		if other/c.h == row/c.h && compareValues(c.grid[row], c.grid[other]) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// stackKey returns the sorted columns of stack as a string, equal for stacks that only differ
+// in the order of their columns.
+func (c *canonicalizer) stackKey(stack int) string {
+	keys := make([]string, c.w)
+	for i := range keys {
+		keys[i] = c.columnKey(stack*c.w + i)
+	}
+	sort.Strings(keys)
+	key := ""
+	for _, k := range keys {
+		key += k
+	}
+	return key
+}
+
+// placeRows places a row of the grid at position pos of the relabelled matrix. labels maps the
+// digits of the grid to their relabelled digits (0 if not yet seen), and next is the next unused
+// label. A new band is started at the first position of each band, from any row of an unused
+// band. Otherwise, the row is chosen from the unused rows of the current band.
+func (c *canonicalizer) placeRows(pos int, labels []PuzzleInt, next PuzzleInt, used []bool) {
+	if pos == c.side {
+		if c.first || compareValues(c.cur, c.best) < 0 {
+			copy(c.best, c.cur)
+			c.first = false
+		}
+		return
+	}
+
+	var candidates []int
+	if pos%c.h == 0 {
+		for row := range used {
+			if !used[row] {
+				candidates = append(candidates, row)
+			}
+		}
+	} else {
+		band := c.bands[len(c.bands)-1]
+		for row := band * c.h; row < (band+1)*c.h; row++ {
+			if !used[row] {
+				candidates = append(candidates, row)
+			}
+		}
+	}
+
+	// Relabel each candidate row, keeping the smallest ones. Identical rows of the same band are
+	// interchangeable, so only the first of them is kept.
+	type candidate struct {
+		row    int
+		labels []PuzzleInt
+		next   PuzzleInt
+		values []PuzzleInt
+	}
+	var smallest []candidate
+	for i, row := range candidates {
+		if c.repeatsRow(row, candidates[:i]) {
+			continue
+		}
+
+		cand := candidate{row: row, labels: append([]PuzzleInt(nil), labels...), next: next}
+		cand.values = make([]PuzzleInt, c.side)
+		for i, col := range c.cols {
+			v := c.grid[row][col]
+			if v != 0 && cand.labels[v] == 0 {
+				cand.labels[v] = cand.next
+				cand.next++
+			}
+			cand.values[i] = cand.labels[v]
+		}
+
+		if len(smallest) != 0 {
+			cmp := compareValues(cand.values, smallest[0].values)
+			if cmp > 0 {
+				continue
+			}
+			if cmp < 0 {
+				smallest = smallest[:0]
+			}
+		}
+		smallest = append(smallest, cand)
+	}
+
+	// Prune the rows whose prefix compares above the best matrix.
+	row := c.cur[pos*c.side : (pos+1)*c.side]
+	copy(row, smallest[0].values)
+	if !c.first && compareValues(c.cur[:(pos+1)*c.side], c.best[:(pos+1)*c.side]) > 0 {
+		return
+	}
+	for _, cand := range smallest {
+		copy(row, cand.values)
+		used[cand.row] = true
+		if pos%c.h == 0 {
+			c.bands = append(c.bands, cand.row/c.h)
+		}
+		c.placeRows(pos+1, cand.labels, cand.next, used)
+		if pos%c.h == 0 {
+			c.bands = c.bands[:len(c.bands)-1]
+		}
+		used[cand.row] = false
+	}
+}
+
+// compareValues compares a and b lexicographically, returning -1, 0, or 1 if a is less than,
+// equal to, or greater than b, respectively.
+func compareValues(a, b []PuzzleInt) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
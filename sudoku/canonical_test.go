@@ -0,0 +1,154 @@
+package sudoku
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// disguise returns a random puzzle equivalent to p, relabelling its digits, permuting its rows
+// within bands, bands, columns within stacks, and stacks, and transposing square boxes.
+func disguise(t *testing.T, r *rand.Rand, p Puzzle) Puzzle {
+	h, w := p.BoxDimensions()
+	side := len(p.Arr)
+	rows := shuffledLines(r, side/int(h), int(h))
+	cols := shuffledLines(r, side/int(w), int(w))
+	digits := r.Perm(side)
+	transpose := h == w && r.Intn(2) == 1
+
+	arr := make([][]PuzzleInt, side)
+	for i := range arr {
+		arr[i] = make([]PuzzleInt, side)
+		for j := range arr[i] {
+			v := p.Arr[rows[i]][cols[j]]
+			if transpose {
+				v = p.Arr[rows[j]][cols[i]]
+			}
+			if v != 0 {
+				v = PuzzleInt(digits[v-1] + 1)
+			}
+			arr[i][j] = v
+		}
+	}
+	disguised, err := NewPuzzle(arr, WithBoxDimensions(h, w))
+	require.NoError(t, err)
+	return disguised
+}
+
+func TestCanonical(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	testCases := []struct {
+		name string
+		line string
+	}{
+		{"4x4", "1..4..2..3..4..1"},
+		{"6x6", "2x3:51..2...4.....2.......65..5.......13"},
+		{"9x9", "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"},
+		{"hard", "8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4.."},
+		{"empty", strings.Repeat(".", 81)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			puzzle, err := ParseLine(tc.line)
+			require.NoError(t, err)
+			canonical, err := puzzle.Canonical()
+			require.NoError(t, err)
+			require.Equal(t, puzzle.CountSolutions(2), canonical.CountSolutions(2))
+			require.Equal(t, countClues(puzzle), countClues(canonical))
+
+			// The canonical form is its own canonical form.
+			again, err := canonical.Canonical()
+			require.NoError(t, err)
+			require.Equal(t, canonical.Arr, again.Arr)
+
+			// Equivalent puzzles have the same canonical form.
+			for i := 0; i < 5; i++ {
+				disguised, err := disguise(t, r, puzzle).Canonical()
+				require.NoError(t, err)
+				require.Equal(t, canonical.Arr, disguised.Arr)
+			}
+		})
+	}
+}
+
+// TestCanonicalMinimal compares the canonical form of 4x4 puzzles with the smallest of all of
+// their equivalent puzzles.
+func TestCanonicalMinimal(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 10; i++ {
+		grid, err := NewPuzzle(randomGrid(r, 2, 2))
+		require.NoError(t, err)
+		for _, pos := range r.Perm(16)[:r.Intn(12)] {
+			grid.Arr[pos/4][pos%4] = 0
+		}
+
+		canonical, err := grid.Canonical()
+		require.NoError(t, err)
+		require.Equal(t, smallestEquivalent(grid), canonical.Arr)
+	}
+}
+
+// smallestEquivalent returns the smallest relabelled matrix of every equivalent 4x4 puzzle.
+func smallestEquivalent(p Puzzle) [][]PuzzleInt {
+	perms := [][]int{
+		{0, 1, 2, 3}, {1, 0, 2, 3}, {0, 1, 3, 2}, {1, 0, 3, 2},
+		{2, 3, 0, 1}, {3, 2, 0, 1}, {2, 3, 1, 0}, {3, 2, 1, 0},
+	}
+	var best []PuzzleInt
+	for transpose := 0; transpose < 2; transpose++ {
+		for _, rows := range perms {
+			for _, cols := range perms {
+				labels := make([]PuzzleInt, 5)
+				next := PuzzleInt(1)
+				values := make([]PuzzleInt, 0, 16)
+				for i := 0; i < 4; i++ {
+					for j := 0; j < 4; j++ {
+						v := p.Arr[rows[i]][cols[j]]
+						if transpose == 1 {
+							v = p.Arr[rows[j]][cols[i]]
+						}
+						if v != 0 && labels[v] == 0 {
+							labels[v] = next
+							next++
+						}
+						values = append(values, labels[v])
+					}
+				}
+				if best == nil || compareValues(values, best) < 0 {
+					best = values
+				}
+			}
+		}
+	}
+	arr := make([][]PuzzleInt, 4)
+	for i := range arr {
+		arr[i] = best[i*4 : (i+1)*4]
+	}
+	return arr
+}
+
+func TestCanonicalTooLarge(t *testing.T) {
+	puzzle, err := ParseLine(strings.Repeat(".", 16*16))
+	require.NoError(t, err)
+	_, err = puzzle.Canonical()
+	require.ErrorIs(t, err, ErrCanonicalSize)
+}
+
+func TestCanonicalVariant(t *testing.T) {
+	puzzle, err := NewPuzzle(emptyArr(4), WithConstraints(diagonal4x4))
+	require.NoError(t, err)
+	_, err = puzzle.Canonical()
+	require.ErrorIs(t, err, ErrCanonicalVariant)
+}
+
+func BenchmarkCanonical(b *testing.B) {
+	puzzle, err := ParseLine("8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4..")
+	require.NoError(b, err)
+	for i := 0; i < b.N; i++ {
+		if _, err := puzzle.Canonical(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
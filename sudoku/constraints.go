@@ -0,0 +1,198 @@
+package sudoku
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Constraint represents a rule of a puzzle, made of units: groups of cells where a value may only
+// appear once. Units with as many cells as the side of the puzzle must hold every value, while
+// smaller units only rule out repeated values.
+//
+// The rows, columns, and boxes of a puzzle are built-in constraints (see Rows, Columns, and
+// Boxes). Variant rules are added with WithConstraints, and are then followed by every solver.
+type Constraint interface {
+	// Type returns the type of the units of the constraint, which conflicts are reported with
+	// (see Validate).
+	Type() UnitType
+	// Units returns the cells of each unit of the constraint in p. Units is called once, when
+	// constructing p, so only the dimensions of p may be relied on.
+	Units(p Puzzle) [][]Cell
+}
+
+// Built-in constraints of every puzzle.
+var (
+	// Rows requires every value to appear once in each row.
+	Rows Constraint = rowConstraint{}
+	// Columns requires every value to appear once in each column.
+	Columns Constraint = columnConstraint{}
+	// Boxes requires every value to appear once in each box (see WithBoxDimensions).
+	Boxes Constraint = boxConstraint{}
+)
+
+// Variant constraints, added with WithConstraints.
+var (
+	// Diagonals requires every value to appear once in each of the two main diagonals, as in
+	// X-Sudoku (see WithDiagonals).
+	Diagonals Constraint = diagonalConstraint{}
+)
+
+// ErrUnit is returned when a constraint has a unit with cells out of the puzzle bounds, or
+// repeated cells.
+var ErrUnit = errors.New("malformed constraint unit")
+
+type rowConstraint struct{}
+
+func (rowConstraint) Type() UnitType { return UnitRow }
+
+func (rowConstraint) Units(p Puzzle) [][]Cell {
+	side := PuzzleInt(len(p.Arr))
+	units := make([][]Cell, side)
+	for row := range units {
+		units[row] = make([]Cell, side)
+		for col := range units[row] {
+			units[row][col] = Cell{PuzzleInt(row), PuzzleInt(col)}
+		}
+	}
+	return units
+}
+
+type columnConstraint struct{}
+
+func (columnConstraint) Type() UnitType { return UnitColumn }
+
+func (columnConstraint) Units(p Puzzle) [][]Cell {
+	side := PuzzleInt(len(p.Arr))
+	units := make([][]Cell, side)
+	for col := range units {
+		units[col] = make([]Cell, side)
+		for row := range units[col] {
+			units[col][row] = Cell{PuzzleInt(row), PuzzleInt(col)}
+		}
+	}
+	return units
+}
+
+type boxConstraint struct{}
+
+func (boxConstraint) Type() UnitType { return UnitBox }
+
+func (boxConstraint) Units(p Puzzle) [][]Cell {
+	side := PuzzleInt(len(p.Arr))
+	var units [][]Cell
+
+	// Boxes, iterated from their top left position.
+	for boxRow := PuzzleInt(0); boxRow < side; boxRow += p.boxHeight {
+		for boxCol := PuzzleInt(0); boxCol < side; boxCol += p.boxWidth {
+			box := make([]Cell, 0, p.boxHeight*p.boxWidth)
+			for row := boxRow; row < boxRow+p.boxHeight; row++ {
+				for col := boxCol; col < boxCol+p.boxWidth; col++ {
+					box = append(box, Cell{row, col})
+				}
+			}
+			units = append(units, box)
+		}
+	}
+	return units
+}
+
+type diagonalConstraint struct{}
+
+func (diagonalConstraint) Type() UnitType { return UnitDiagonal }
+
+func (diagonalConstraint) Units(p Puzzle) [][]Cell {
+	side := PuzzleInt(len(p.Arr))
+	main, anti := make([]Cell, side), make([]Cell, side)
+	for i := PuzzleInt(0); i < side; i++ {
+		main[i] = Cell{i, i}
+		anti[i] = Cell{i, side - 1 - i}
+	}
+	return [][]Cell{main, anti}
+}
+
+// buildUnits lists the units of the constraints of the puzzle, and indexes them by cell. An error
+// wrapping ErrUnit is returned if a unit is malformed.
+func (p *Puzzle) buildUnits() error {
+	side := len(p.Arr)
+	p.units = nil
+	p.cellUnits = make([][]int, side*side)
+	for _, c := range p.constraints {
+		for _, cells := range c.Units(*p) {
+			u := len(p.units)
+			for _, cell := range cells {
+				if int(cell.Row) >= side || int(cell.Col) >= side {
+					return fmt.Errorf("%w: %s unit has cell at row %d, column %d", ErrUnit, c.Type(),
+						cell.Row, cell.Col)
+				}
+				i := p.cellIndex(cell.Row, cell.Col)
+				if n := len(p.cellUnits[i]); n != 0 && p.cellUnits[i][n-1] == u {
+					return fmt.Errorf("%w: %s unit repeats row %d, column %d", ErrUnit, c.Type(),
+						cell.Row, cell.Col)
+				}
+				p.cellUnits[i] = append(p.cellUnits[i], u)
+			}
+			p.units = append(p.units, unit{Type: c.Type(), Cells: cells})
+		}
+	}
+	p.unitVals = make([]bitSet, len(p.units))
+	return nil
+}
+
+// standard returns whether or not the only constraints of the puzzle are its rows, columns, and
+// boxes.
+func (p Puzzle) standard() bool {
+	return len(p.constraints) == 3 &&
+		p.constraints[0] == Rows && p.constraints[1] == Columns && p.constraints[2] == Boxes
+}
+
+// hasConstraint returns whether or not c is a constraint of the puzzle.
+func (p Puzzle) hasConstraint(c Constraint) bool {
+	for _, pc := range p.constraints {
+		if pc == c {
+			return true
+		}
+	}
+	return false
+}
+
+// cellIndex returns the index of the row and col position in row-major order.
+func (p Puzzle) cellIndex(row, col PuzzleInt) int {
+	return int(row)*len(p.Arr) + int(col)
+}
+
+// taken returns the values of the units of the vacant row and col position, along with those its
+// cage cannot add up to its sum with (see WithCages), which cannot be placed at the position.
+func (p Puzzle) taken(row, col PuzzleInt) bitSet {
+	var bs bitSet
+	for _, u := range p.cellUnits[p.cellIndex(row, col)] {
+		bs.Union(&p.unitVals[u])
+	}
+	p.cageTaken(row, col, &bs)
+	return bs
+}
+
+// unitContains returns whether or not the unit of type t of the row and col position contains
+// val.
+func (p Puzzle) unitContains(t UnitType, row, col, val PuzzleInt) bool {
+	for _, u := range p.cellUnits[p.cellIndex(row, col)] {
+		if p.units[u].Type == t && p.unitVals[u].Get(int(val)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// rowContains returns whether or not the row contains val.
+func (p Puzzle) rowContains(row, val PuzzleInt) bool {
+	return p.unitContains(UnitRow, row, 0, val)
+}
+
+// colContains returns whether or not the col contains val.
+func (p Puzzle) colContains(col, val PuzzleInt) bool {
+	return p.unitContains(UnitColumn, 0, col, val)
+}
+
+// boxContains returns whether or not the box of the row and col position contains val.
+func (p Puzzle) boxContains(row, col, val PuzzleInt) bool {
+	return p.unitContains(UnitBox, row, col, val)
+}
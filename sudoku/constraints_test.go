@@ -0,0 +1,192 @@
+package sudoku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// unitConstraint is a Constraint with fixed units.
+type unitConstraint struct {
+	t     UnitType
+	units [][]Cell
+}
+
+func (c unitConstraint) Type() UnitType { return c.t }
+
+func (c unitConstraint) Units(p Puzzle) [][]Cell { return c.units }
+
+// unitCustom is the unit type of the test constraints.
+const unitCustom UnitType = 100
+
+// diagonal4x4 requires the main diagonal of a 4x4 puzzle to hold every value.
+var diagonal4x4 = unitConstraint{unitCustom, [][]Cell{{{0, 0}, {1, 1}, {2, 2}, {3, 3}}}}
+
+// corners4x4 requires the top right and bottom left values of a 4x4 puzzle to differ.
+var corners4x4 = unitConstraint{unitCustom, [][]Cell{{{0, 3}, {3, 0}}}}
+
+func TestBuiltinConstraints(t *testing.T) {
+	puzzle, err := NewPuzzle(emptyArr(6), WithBoxDimensions(2, 3))
+	require.NoError(t, err)
+	require.True(t, puzzle.standard())
+	require.Len(t, puzzle.units, 3*6)
+	for i, cells := range Boxes.Units(puzzle) {
+		require.Len(t, cells, 6)
+		// Boxes are 2 rows high and 3 columns wide.
+		require.Equal(t, Cell{PuzzleInt(i / 2 * 2), PuzzleInt(i % 2 * 3)}, cells[0])
+		require.Equal(t, Cell{PuzzleInt(i/2*2 + 1), PuzzleInt(i%2*3 + 2)}, cells[5])
+	}
+	// Every cell is in a row, a column, and a box.
+	for _, units := range puzzle.cellUnits {
+		require.Len(t, units, 3)
+		require.Equal(t, UnitRow, puzzle.units[units[0]].Type)
+		require.Equal(t, UnitColumn, puzzle.units[units[1]].Type)
+		require.Equal(t, UnitBox, puzzle.units[units[2]].Type)
+	}
+}
+
+func TestWithConstraints(t *testing.T) {
+	testCases := []struct {
+		name        string
+		constraints []Constraint
+		count       int
+	}{
+		{"none", nil, 288},
+		{"diagonal", []Constraint{diagonal4x4}, 48},
+		{"corners", []Constraint{corners4x4}, 216},
+	}
+	for _, tc := range testCases {
+		for _, engine := range engines {
+			t.Run(tc.name+"/"+engine.name, func(t *testing.T) {
+				opts := append([]PuzzleOption{WithConstraints(tc.constraints...)}, engine.opts...)
+				puzzle, err := NewPuzzle(emptyArr(4), opts...)
+				require.NoError(t, err)
+				require.Equal(t, tc.count, puzzle.CountSolutions(0))
+
+				solution, ok := puzzle.Solved()
+				require.True(t, ok)
+				require.Empty(t, solution.Validate())
+			})
+		}
+	}
+}
+
+func TestConstraintValidate(t *testing.T) {
+	arr := [][]PuzzleInt{
+		{1, 0, 0, 0},
+		{0, 1, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	}
+	puzzle, err := NewPuzzle(arr, WithConstraints(diagonal4x4))
+	require.NoError(t, err)
+	require.False(t, puzzle.isValidPos(2, 2, 1))
+	require.NotContains(t, puzzle.Candidates(3, 3), PuzzleInt(1))
+
+	// The box conflict precedes the diagonal conflict.
+	require.Equal(t, []Conflict{
+		{Unit: UnitBox, Value: 1, A: Cell{0, 0}, B: Cell{1, 1}},
+		{Unit: unitCustom, Value: 1, A: Cell{0, 0}, B: Cell{1, 1}},
+	}, puzzle.Validate())
+}
+
+func TestConstraintErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		units [][]Cell
+	}{
+		{"out of bounds", [][]Cell{{{0, 0}, {4, 0}}}},
+		{"repeated cell", [][]Cell{{{0, 0}, {1, 1}, {0, 0}}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewPuzzle(emptyArr(4), WithConstraints(unitConstraint{unitCustom, tc.units}))
+			require.ErrorIs(t, err, ErrUnit)
+		})
+	}
+}
+
+func TestDiagonals(t *testing.T) {
+	testCases := []struct {
+		name     string
+		line     string
+		solution string
+	}{
+		{
+			name:     "9x9",
+			line:     ".682..4...5...3....21....5..9....8..83..4..1.........55.6..7..1..7..836.2......7.",
+			solution: "X:768251493459673182321894756692315847835746219174982635586437921917528364243169578",
+		},
+		{
+			name:     "6x6",
+			line:     "2x3:.............4.............6.1.3..2.",
+			solution: "X:2x3:412356365142241563653214524631136425",
+		},
+	}
+	for _, tc := range testCases {
+		// Without the diagonals, the puzzles have more than one solution.
+		puzzle, err := ParseLine(tc.line)
+		require.NoError(t, err)
+		require.False(t, puzzle.HasUniqueSolution())
+
+		for _, engine := range engines {
+			t.Run(tc.name+"/"+engine.name, func(t *testing.T) {
+				opts := append([]PuzzleOption{WithDiagonals()}, engine.opts...)
+				puzzle, err := ParseLine(tc.line, opts...)
+				require.NoError(t, err)
+				require.True(t, puzzle.HasUniqueSolution())
+
+				solution, ok := puzzle.Solved()
+				require.True(t, ok)
+				text, err := solution.MarshalText()
+				require.NoError(t, err)
+				require.Equal(t, tc.solution, string(text))
+				require.Empty(t, solution.Validate())
+			})
+		}
+
+		t.Run(tc.name+"/logical", func(t *testing.T) {
+			puzzle, err := ParseLine(tc.line, WithDiagonals())
+			require.NoError(t, err)
+			res := puzzle.SolveLogical()
+			require.True(t, res.Solved)
+			solution, err := ParseLine(tc.solution)
+			require.NoError(t, err)
+			require.Equal(t, solution.Arr, res.Arr)
+		})
+	}
+}
+
+func TestDiagonalsValidate(t *testing.T) {
+	arr := emptyArr(4)
+	arr[0][3], arr[3][0] = 2, 2
+	puzzle, err := NewPuzzle(arr, WithDiagonals())
+	require.NoError(t, err)
+	require.Equal(t, []Conflict{
+		{Unit: UnitDiagonal, Value: 2, A: Cell{0, 3}, B: Cell{3, 0}},
+	}, puzzle.Validate())
+	require.False(t, puzzle.isValidPos(1, 2, 2))
+	require.True(t, puzzle.isValidPos(1, 1, 2))
+}
+
+func TestDiagonalsPretty(t *testing.T) {
+	arr := [][]PuzzleInt{
+		{1, 0, 0, 4},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{4, 0, 0, 1},
+	}
+	puzzle, err := NewPuzzle(arr, WithDiagonals())
+	require.NoError(t, err)
+	require.Equal(t, ""+
+		"1*  |   4*\n"+
+		"   *|  *  \n"+
+		"- - - - - \n"+
+		"   *|  *  \n"+
+		"4*  |   1*\n", puzzle.Pretty())
+
+	// Puzzles without the diagonals are left unmarked.
+	puzzle, err = NewPuzzle(copyArr(arr))
+	require.NoError(t, err)
+	require.NotContains(t, puzzle.Pretty(), "*")
+}
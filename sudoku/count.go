@@ -0,0 +1,37 @@
+package sudoku
+
+import "context"
+
+// CountSolutions returns the number of solutions of the puzzle, continuing the search after
+// each solution is found. The search stops once limit solutions are found, unless limit is not
+// positive, in which case all solutions are counted. The search also stops once the budget of the
+// puzzle is exceeded, returning the solutions found so far (see CountContext). The puzzle is left
+// untouched.
+func (p Puzzle) CountSolutions(limit int) int {
+	res, _ := p.CountContext(context.Background(), limit)
+	return res.Solutions
+}
+
+// CountContext counts the solutions of the puzzle like CountSolutions, stopping the search once
+// ctx is done or the budget of the puzzle is exceeded (see WithMaxNodes and WithMaxDuration).
+// The search is done by the solver of the puzzle, if it is a Counter, otherwise by Backtracking.
+//
+// CountContext returns the statistics of the search, and an error wrapping ErrBudgetExceeded or
+// the error of ctx when the search was interrupted, in which case the solutions found so far are
+// counted.
+func (p Puzzle) CountContext(ctx context.Context, limit int) (Result, error) {
+	counter, ok := p.solver.(Counter)
+	if !ok {
+		counter = Backtracking.(Counter)
+	}
+	return counter.Count(ctx, &p, limit)
+}
+
+// HasUniqueSolution returns whether or not the puzzle has exactly one solution. Puzzles whose
+// search is interrupted by their budget (see CountContext) are not known to be unique, so false
+// is returned for them.
+func (p Puzzle) HasUniqueSolution() bool {
+	// Finding a second solution is enough to rule out uniqueness.
+	res, err := p.CountContext(context.Background(), 2)
+	return err == nil && res.Solutions == 1
+}
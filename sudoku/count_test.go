@@ -0,0 +1,149 @@
+package sudoku
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// emptyArr returns a side x side matrix of empty positions.
+func emptyArr(side int) [][]PuzzleInt {
+	arr := make([][]PuzzleInt, side)
+	for i := range arr {
+		arr[i] = make([]PuzzleInt, side)
+	}
+	return arr
+}
+
+// stuckArr returns a 9x9 matrix without a solution, as column 8 leaves the 9 already in row 8 for
+// its last position, which backtracking only finds out after exhausting the rest of the grid.
+func stuckArr() [][]PuzzleInt {
+	arr := emptyArr(9)
+	for row := 0; row < 8; row++ {
+		arr[row][8] = PuzzleInt(row + 1)
+	}
+	arr[8][0] = 9
+	return arr
+}
+
+// boxlessArr returns a 16x16 matrix without a solution, as the 1s outside of the first box rule
+// out every cell of it but the one holding 2. Every cell keeps several candidates, so even
+// MinRemainingValues only finds out after a long search.
+func boxlessArr() [][]PuzzleInt {
+	arr := emptyArr(16)
+	arr[0][5], arr[1][9], arr[2][13] = 1, 1, 1
+	arr[5][0], arr[9][1], arr[13][2] = 1, 1, 1
+	arr[3][3] = 2
+	return arr
+}
+
+func TestCountSolutions(t *testing.T) {
+	testCases := []struct {
+		arr    [][]PuzzleInt
+		limit  int
+		count  int
+		unique bool
+	}{
+		{
+			arr: [][]PuzzleInt{
+				{8, 0, 0, 4, 0, 0, 9, 1, 0},
+				{0, 0, 3, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 3, 0, 0, 4},
+				{0, 0, 0, 0, 0, 1, 0, 4, 0},
+				{0, 5, 8, 0, 0, 0, 7, 0, 0},
+				{0, 7, 0, 0, 0, 6, 8, 0, 0},
+				{0, 0, 0, 0, 0, 2, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 1, 6, 0},
+				{9, 1, 0, 0, 6, 0, 5, 0, 0},
+			},
+			count:  1,
+			unique: true,
+		},
+		{
+			// The number of 4x4 Sudoku grids.
+			arr:   emptyArr(4),
+			count: 288,
+		},
+		{
+			arr:   emptyArr(4),
+			limit: 10,
+			count: 10,
+		},
+		{
+			// The values 1 and 2 can be swapped between the vacant positions.
+			arr: [][]PuzzleInt{
+				{0, 0, 3, 4},
+				{3, 4, 1, 2},
+				{0, 0, 4, 3},
+				{4, 3, 2, 1},
+			},
+			count: 2,
+		},
+		{
+			// Conflicting values.
+			arr: [][]PuzzleInt{
+				{1, 1, 0, 0},
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+			},
+			count: 0,
+		},
+	}
+	for _, tc := range testCases {
+		// Keep a copy of the matrix to ensure it is untouched.
+		original := copyArr(tc.arr)
+
+		for _, engine := range engines {
+			puzzle, err := NewPuzzle(copyArr(tc.arr), engine.opts...)
+			require.NoError(t, err)
+			require.Equal(t, tc.count, puzzle.CountSolutions(tc.limit), engine.name)
+			require.Equal(t, tc.unique, puzzle.HasUniqueSolution(), engine.name)
+			require.Equal(t, original, puzzle.Arr, engine.name)
+
+			// The puzzle is still solvable after counting.
+			require.Equal(t, tc.count != 0, puzzle.Solve(), engine.name)
+		}
+	}
+}
+
+func TestCountContext(t *testing.T) {
+	puzzle, err := NewPuzzle(emptyArr(4))
+	require.NoError(t, err)
+	res, err := puzzle.CountContext(context.Background(), 0)
+	require.NoError(t, err)
+	require.Equal(t, 288, res.Solutions)
+
+	// Counting honours the budget of the puzzle.
+	puzzle, err = NewPuzzle(stuckArr(), WithMaxNodes(1000))
+	require.NoError(t, err)
+	_, err = puzzle.CountContext(context.Background(), 2)
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+	require.Equal(t, stuckArr(), puzzle.Arr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = puzzle.CountContext(ctx, 2)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestHasUniqueSolutionBudget(t *testing.T) {
+	// The budget runs out after the first solution, before a second one is found.
+	puzzle, err := NewPuzzle(emptyArr(4), WithMaxNodes(17))
+	require.NoError(t, err)
+	res, err := puzzle.CountContext(context.Background(), 2)
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+	require.Equal(t, 1, res.Solutions)
+	require.False(t, puzzle.HasUniqueSolution())
+
+	// Unique puzzles within the budget are still reported as unique.
+	puzzle, err = NewPuzzle([][]PuzzleInt{
+		{1, 2, 3, 4},
+		{3, 4, 1, 2},
+		{2, 1, 4, 3},
+		{4, 3, 2, 0},
+	}, WithMaxNodes(17))
+	require.NoError(t, err)
+	require.True(t, puzzle.HasUniqueSolution())
+}
@@ -0,0 +1,205 @@
+package sudoku
+
+// dlx represents the exact cover matrix of a puzzle, linked as Dancing Links. Every matrix row is
+// a candidate (a value at a vacant position), and every matrix column is a constraint that must
+// be covered exactly once:
+//
+//	[0, side²)                   a value at each position
+//	[side², side² + units·side)  each value in each unit, such as a row, column, or box
+//
+// Units with fewer cells than the side of the puzzle need not hold every value, so their columns
+// are secondary: they may be covered at most once, and are left out of the header list.
+// Constraints already covered by the values of the puzzle are left out of the matrix. The sums of
+// Killer cages cannot be expressed as exact cover, so they are checked as rows are selected.
+//
+// Nodes are indices into the link slices, where node 0 is the root, the nodes [1, columns] are
+// the column headers, and the rest are the 1s of the matrix.
+type dlx struct {
+	p                     Puzzle
+	left, right, up, down []int
+	col                   []int       // Column header of each node
+	size                  []int       // Number of nodes in each column, by header
+	cands                 []Candidate // Candidate of the matrix row of each node
+}
+
+// newDLX builds the exact cover matrix of the vacant positions of p. p must not have conflicting
+// values (see Validate).
+func newDLX(p Puzzle) *dlx {
+	side := len(p.Arr)
+	columns := side*side + len(p.units)*side
+
+	// constraints returns the column headers of the candidate val at the row and col position.
+	constraints := func(row, col, val int) []int {
+		units := p.cellUnits[p.cellIndex(PuzzleInt(row), PuzzleInt(col))]
+		headers := make([]int, 0, 1+len(units))
+		headers = append(headers, 1+row*side+col)
+		for _, u := range units {
+			headers = append(headers, 1+side*side+u*side+val-1)
+		}
+		return headers
+	}
+
+	// Mark the constraints covered by the values of the puzzle, and the secondary constraints.
+	covered := make([]bool, columns+1)
+	for row := range p.Arr {
+		for col, val := range p.Arr[row] {
+			if val == 0 {
+				continue
+			}
+			for _, c := range constraints(row, col, int(val)) {
+				covered[c] = true
+			}
+		}
+	}
+	for u, un := range p.units {
+		if len(un.Cells) == side {
+			continue
+		}
+		for val := 1; val <= side; val++ {
+			covered[1+side*side+u*side+val-1] = true
+		}
+	}
+
+	// List the candidates of the vacant positions, the rows of the matrix, counting their nodes.
+	var cands []Candidate
+	nodes := columns + 1
+	for row := range p.Arr {
+		for col, v := range p.Arr[row] {
+			if v != 0 {
+				continue
+			}
+			for val := PuzzleInt(1); val <= PuzzleInt(side); val++ {
+				if p.isValidPos(PuzzleInt(row), PuzzleInt(col), val) {
+					cands = append(cands, Candidate{Cell{PuzzleInt(row), PuzzleInt(col)}, val})
+					nodes += 1 + len(p.cellUnits[p.cellIndex(PuzzleInt(row), PuzzleInt(col))])
+				}
+			}
+		}
+	}
+
+	// Allocate the root, the headers, and a node per constraint of every candidate at once.
+	d := &dlx{
+		p:     p,
+		left:  make([]int, nodes),
+		right: make([]int, nodes),
+		up:    make([]int, nodes),
+		down:  make([]int, nodes),
+		col:   make([]int, nodes),
+		size:  make([]int, columns+1),
+		cands: make([]Candidate, nodes),
+	}
+
+	// Link the root and the headers of the uncovered constraints.
+	last := 0
+	for c := 1; c <= columns; c++ {
+		d.up[c], d.down[c], d.col[c] = c, c, c
+		if covered[c] {
+			d.left[c], d.right[c] = c, c
+			continue
+		}
+		d.left[c], d.right[last] = last, c
+		last = c
+	}
+	d.right[last], d.left[0] = 0, last
+
+	first := columns + 1
+	for _, c := range cands {
+		headers := constraints(int(c.Row), int(c.Col), int(c.Value))
+		d.addRow(first, c, headers)
+		first += len(headers)
+	}
+	return d
+}
+
+// addRow links a matrix row for the candidate c, starting at the node first, with a node in each
+// of the columns.
+func (d *dlx) addRow(first int, c Candidate, columns []int) {
+	for i, h := range columns {
+		n := first + i
+		// Link horizontally, in a circle.
+		d.left[n] = first + (i+len(columns)-1)%len(columns)
+		d.right[n] = first + (i+1)%len(columns)
+		// Link vertically, at the bottom of the column.
+		d.up[n], d.down[n] = d.up[h], h
+		d.down[d.up[h]], d.up[h] = n, n
+		d.col[n] = h
+		d.size[h]++
+		d.cands[n] = c
+	}
+}
+
+// cover removes the column c from the header list, and the rows of its nodes from the other
+// columns.
+func (d *dlx) cover(c int) {
+	d.right[d.left[c]], d.left[d.right[c]] = d.right[c], d.left[c]
+	for i := d.down[c]; i != c; i = d.down[i] {
+		for j := d.right[i]; j != i; j = d.right[j] {
+			d.down[d.up[j]], d.up[d.down[j]] = d.down[j], d.up[j]
+			d.size[d.col[j]]--
+		}
+	}
+}
+
+// uncover restores the column c, undoing cover in reverse order.
+func (d *dlx) uncover(c int) {
+	for i := d.up[c]; i != c; i = d.up[i] {
+		for j := d.left[i]; j != i; j = d.left[j] {
+			d.size[d.col[j]]++
+			d.down[d.up[j]], d.up[d.down[j]] = j, j
+		}
+	}
+	d.right[d.left[c]], d.left[d.right[c]] = c, c
+}
+
+// search recursively selects matrix rows until every column is covered, placing the candidates
+// of the selected rows in the puzzle and calling found on s for every solution. search returns
+// true when the search should stop, leaving the solution in place.
+func (d *dlx) search(s *search) bool {
+	// Stop when canceled or over budget.
+	if s.visit() {
+		return true
+	}
+	if d.right[0] == 0 {
+		// Every constraint is covered, a solution was found.
+		return s.found()
+	}
+
+	// Choose the column with the fewest rows, failing early on uncoverable columns.
+	c := d.right[0]
+	for j := d.right[c]; j != 0; j = d.right[j] {
+		if d.size[j] < d.size[c] {
+			c = j
+		}
+	}
+	if d.size[c] == 0 {
+		return false
+	}
+
+	// Try every row of the column, recurse, and backtrack.
+	d.cover(c)
+	for r := d.down[c]; r != c; r = d.down[r] {
+		cand := d.cands[r]
+		// Cage sums are not columns of the matrix, so skip candidates their cage cannot add up with.
+		if d.p.cellCages != nil {
+			var cage bitSet
+			d.p.cageTaken(cand.Row, cand.Col, &cage)
+			if cage.Get(int(cand.Value)) == 1 {
+				continue
+			}
+		}
+		for j := d.right[r]; j != r; j = d.right[j] {
+			d.cover(d.col[j])
+		}
+		d.p.set(cand.Row, cand.Col, cand.Value)
+		if d.search(s) {
+			return true
+		}
+		d.p.unset(cand.Row, cand.Col, cand.Value)
+		s.Backtracks++
+		for j := d.left[r]; j != r; j = d.left[j] {
+			d.uncover(d.col[j])
+		}
+	}
+	d.uncover(c)
+	return false
+}
@@ -0,0 +1,74 @@
+package sudoku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// dlxColumns returns the number of columns linked in the header list of d.
+func dlxColumns(d *dlx) int {
+	n := 0
+	for c := d.right[0]; c != 0; c = d.right[c] {
+		n++
+	}
+	return n
+}
+
+func TestNewDLX(t *testing.T) {
+	// An empty puzzle has every constraint, and every value at every position.
+	puzzle, err := NewPuzzle(emptyArr(4))
+	require.NoError(t, err)
+	d := newDLX(puzzle)
+	require.Equal(t, 4*4*4, dlxColumns(d))
+	require.Equal(t, 1+4*4*4+4*(4*4*4), len(d.left))
+
+	// The constraints covered by the values are left out, with their candidates.
+	arr := emptyArr(4)
+	arr[0][0] = 1
+	puzzle, err = NewPuzzle(arr)
+	require.NoError(t, err)
+	d = newDLX(puzzle)
+	require.Equal(t, 4*4*4-4, dlxColumns(d))
+	for c := d.right[0]; c != 0; c = d.right[c] {
+		for n := d.down[c]; n != c; n = d.down[n] {
+			cand := d.cands[n]
+			require.NotEqual(t, Cell{0, 0}, cand.Cell)
+			require.True(t, puzzle.isValidPos(cand.Row, cand.Col, cand.Value))
+		}
+	}
+
+	// A complete puzzle has nothing left to cover.
+	arr = [][]PuzzleInt{
+		{1, 2, 3, 4},
+		{3, 4, 1, 2},
+		{2, 1, 4, 3},
+		{4, 3, 2, 1},
+	}
+	puzzle, err = NewPuzzle(arr)
+	require.NoError(t, err)
+	d = newDLX(puzzle)
+	require.Equal(t, 0, dlxColumns(d))
+	require.True(t, d.search(&search{limit: 1}))
+}
+
+func TestDLXCoverUncover(t *testing.T) {
+	puzzle, err := NewPuzzle(emptyArr(4))
+	require.NoError(t, err)
+	d := newDLX(puzzle)
+	left, right := append([]int(nil), d.left...), append([]int(nil), d.right...)
+	up, down := append([]int(nil), d.up...), append([]int(nil), d.down...)
+	size := append([]int(nil), d.size...)
+
+	// Uncovering in reverse order restores every link.
+	d.cover(1)
+	d.cover(17)
+	require.Equal(t, 4*4*4-2, dlxColumns(d))
+	d.uncover(17)
+	d.uncover(1)
+	require.Equal(t, left, d.left)
+	require.Equal(t, right, d.right)
+	require.Equal(t, up, d.up)
+	require.Equal(t, down, d.down)
+	require.Equal(t, size, d.size)
+}
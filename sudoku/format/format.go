@@ -0,0 +1,238 @@
+// Package format parses and emits Sudoku puzzles in the interchange formats of other Sudoku
+// tools: the compact line format, SadMan .sdk files, HoDoKu pencil-mark grids, and the layout of
+// sudoku.Puzzle.Pretty. The format of an input can be detected automatically:
+//
+//	puzzle, f, err := format.Parse(input)
+//	if err != nil {
+//		fmt.Println("Malformed puzzle:", err) // Reports the line and column, see ParseError
+//		return
+//	}
+//	out, err := format.Emit(puzzle, format.SDK)
+package format
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/husseinelguindi/sudoku-api/sudoku"
+)
+
+// Format represents a textual format of a puzzle.
+type Format int
+
+// Supported formats.
+const (
+	// Line is the line format of sudoku.Puzzle.MarshalText, such as "1.3.....".
+	Line Format = iota
+	// SDK is the SadMan Software .sdk format, a row of digits per line with '.' for blanks,
+	// optionally preceded by "#" metadata lines.
+	SDK
+	// PencilMarks is the HoDoKu pencil-mark grid, listing the candidates of every vacant position
+	// in a framed grid. A position with a single digit holds that value.
+	PencilMarks
+	// Pretty is the layout of sudoku.Puzzle.Pretty.
+	Pretty
+)
+
+// formatNames maps a Format to its name.
+var formatNames = map[Format]string{
+	Line:        "line",
+	SDK:         "sdk",
+	PencilMarks: "pencil_marks",
+	Pretty:      "pretty",
+}
+
+// String implements the Stringer interface for Format.
+func (f Format) String() string {
+	if name, ok := formatNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for Format, encoding it by name.
+func (f Format) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for Format, decoding it by name.
+func (f *Format) UnmarshalText(text []byte) error {
+	for format, name := range formatNames {
+		if name == string(text) {
+			*f = format
+			return nil
+		}
+	}
+	return fmt.Errorf("%w %q", ErrUnknownFormat, text)
+}
+
+// Errors returned when parsing or emitting a puzzle.
+var (
+	ErrUnknownFormat = errors.New("unknown puzzle format")
+	ErrSyntax        = errors.New("invalid syntax")
+	ErrTooLarge      = errors.New("puzzle side exceeds the digits of the format")
+)
+
+// ParseError represents a malformed input, at a line and column of the input (both starting
+// at 1). Err is the cause, which wraps ErrSyntax or an error of sudoku.NewPuzzle.
+type ParseError struct {
+	Format       Format
+	Line, Column int
+	Err          error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s format: line %d, column %d: %v", e.Format, e.Line, e.Column, e.Err)
+}
+
+// Unwrap returns the cause of the error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// maxSide is the largest side of a puzzle whose values are single base-36 digits.
+const maxSide = 35
+
+// Detect returns the format of input, or ErrUnknownFormat if it matches none. Detection only
+// looks at the shape of the input, which may still be malformed.
+func Detect(input string) (Format, error) {
+	lines, _ := splitLines(input)
+	switch {
+	case len(lines) == 0:
+		return 0, ErrUnknownFormat
+	case len(lines) == 1 && !strings.ContainsAny(lines[0], " \t|"):
+		return Line, nil
+	case strings.HasPrefix(lines[0], ".-"):
+		return PencilMarks, nil
+	case strings.HasPrefix(lines[0], "#"):
+		return SDK, nil
+	}
+	// Rows of digits without whitespace are SadMan rows, rows with whitespace are laid out.
+	for _, line := range lines {
+		if strings.ContainsAny(line, " \t|") {
+			return Pretty, nil
+		}
+	}
+	return SDK, nil
+}
+
+// Parse detects the format of input (see Detect) and parses the puzzle it holds. Options may be
+// passed to further configure the puzzle, though box dimensions described by the input take
+// precedence. Malformed input returns a *ParseError.
+func Parse(input string, opts ...sudoku.PuzzleOption) (sudoku.Puzzle, Format, error) {
+	f, err := Detect(input)
+	if err != nil {
+		return sudoku.Puzzle{}, 0, err
+	}
+	puzzle, err := ParseAs(f, input, opts...)
+	return puzzle, f, err
+}
+
+// ParseAs parses the puzzle held by input in the format f. Options may be passed to further
+// configure the puzzle, though box dimensions described by the input take precedence. Malformed
+// input returns a *ParseError.
+func ParseAs(f Format, input string, opts ...sudoku.PuzzleOption) (sudoku.Puzzle, error) {
+	lines, first := splitLines(input)
+	if len(lines) == 0 {
+		return sudoku.Puzzle{}, &ParseError{f, 1, 1, fmt.Errorf("%w: no puzzle", ErrSyntax)}
+	}
+	switch f {
+	case Line:
+		return parseLine(lines, first, opts)
+	case SDK:
+		return parseSDK(lines, first, opts)
+	case PencilMarks:
+		return parsePencilMarks(lines, first, opts)
+	case Pretty:
+		return parsePretty(lines, first, opts)
+	}
+	return sudoku.Puzzle{}, fmt.Errorf("%w: %d", ErrUnknownFormat, int(f))
+}
+
+// Emit returns the puzzle in the format f. Formats of single digits cannot emit puzzles with a
+// side larger than 35, and return ErrTooLarge.
+func Emit(p sudoku.Puzzle, f Format) (string, error) {
+	switch f {
+	case Line:
+		text, err := p.MarshalText()
+		if errors.Is(err, sudoku.ErrLineFormat) {
+			return "", fmt.Errorf("%w: %v", ErrTooLarge, err)
+		}
+		return string(text) + "\n", err
+	case SDK:
+		return emitSDK(p)
+	case PencilMarks:
+		return emitPencilMarks(p)
+	case Pretty:
+		return p.Pretty(), nil
+	}
+	return "", fmt.Errorf("%w: %d", ErrUnknownFormat, int(f))
+}
+
+// splitLines splits input into lines, without line endings or trailing whitespace, dropping the
+// blank lines around the puzzle. first is the line number of the first line kept.
+func splitLines(input string) (lines []string, first int) {
+	lines = strings.Split(strings.ReplaceAll(input, "\r\n", "\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \t\r")
+	}
+	first = 1
+	for len(lines) != 0 && lines[0] == "" {
+		lines = lines[1:]
+		first++
+	}
+	for len(lines) != 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines, first
+}
+
+// digitValue returns the value of a base-36 digit, 1-9 then letters (in either case) from 10. '.'
+// and '0' are vacant positions. ok is false for other characters.
+func digitValue(c byte) (val sudoku.PuzzleInt, ok bool) {
+	switch {
+	case c == '.' || c == '0':
+		return 0, true
+	case c >= '1' && c <= '9':
+		return sudoku.PuzzleInt(c - '0'), true
+	case c >= 'A' && c <= 'Z':
+		return sudoku.PuzzleInt(c-'A') + 10, true
+	case c >= 'a' && c <= 'z':
+		return sudoku.PuzzleInt(c-'a') + 10, true
+	}
+	return 0, false
+}
+
+// digit returns the base-36 digit of a value, with '.' for vacant positions.
+func digit(val sudoku.PuzzleInt) byte {
+	switch {
+	case val == 0:
+		return '.'
+	case val < 10:
+		return byte('0' + val)
+	}
+	return byte('A' + val - 10)
+}
+
+// newMatrix returns a side x side matrix of vacant positions.
+func newMatrix(side int) [][]sudoku.PuzzleInt {
+	arr := make([][]sudoku.PuzzleInt, side)
+	for i := range arr {
+		arr[i] = make([]sudoku.PuzzleInt, side)
+	}
+	return arr
+}
+
+// newPuzzle constructs the puzzle parsed in the format f, starting at the line first, with its box
+// dimensions following opts. Zero box dimensions keep the default of sudoku.NewPuzzle.
+func newPuzzle(f Format, first int, arr [][]sudoku.PuzzleInt, boxHeight, boxWidth int, opts []sudoku.PuzzleOption) (sudoku.Puzzle, error) {
+	if boxHeight != 0 && boxWidth != 0 {
+		opts = append(opts, sudoku.WithBoxDimensions(sudoku.PuzzleInt(boxHeight), sudoku.PuzzleInt(boxWidth)))
+	}
+	puzzle, err := sudoku.NewPuzzle(arr, opts...)
+	if err != nil {
+		return sudoku.Puzzle{}, &ParseError{f, first, 1, err}
+	}
+	return puzzle, nil
+}
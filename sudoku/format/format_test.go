@@ -0,0 +1,129 @@
+package format
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/husseinelguindi/sudoku-api/sudoku"
+	"github.com/stretchr/testify/require"
+)
+
+// Puzzles shared by the tests of every format.
+const (
+	hardLine = "800400910003000000000003004000001040058000700070006800000002000000000160910060500"
+	rectLine = "2x3:51..2...4.....2.......65..5.......13"
+)
+
+// mustParseLine returns the puzzle of a line, failing the test otherwise.
+func mustParseLine(t *testing.T, line string) sudoku.Puzzle {
+	puzzle, err := sudoku.ParseLine(line)
+	require.NoError(t, err)
+	return puzzle
+}
+
+// requireParseError requires err to be a *ParseError at line and column, wrapping target.
+func requireParseError(t *testing.T, err error, line, column int, target error) {
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr), "%v is not a *ParseError", err)
+	require.Equal(t, line, parseErr.Line, err.Error())
+	require.Equal(t, column, parseErr.Column, err.Error())
+	require.ErrorIs(t, err, target)
+}
+
+func TestRoundTrip(t *testing.T) {
+	solved := mustParseLine(t, hardLine)
+	require.True(t, solved.Solve())
+	arr := make([][]sudoku.PuzzleInt, 16)
+	for i := range arr {
+		arr[i] = make([]sudoku.PuzzleInt, 16)
+	}
+	arr[0][0], arr[3][15], arr[15][7] = 16, 10, 1
+	large, err := sudoku.NewPuzzle(arr)
+	require.NoError(t, err)
+
+	puzzles := []sudoku.Puzzle{mustParseLine(t, hardLine), mustParseLine(t, rectLine), solved, large}
+	for _, puzzle := range puzzles {
+		for _, f := range []Format{Line, SDK, PencilMarks, Pretty} {
+			if f == PencilMarks && puzzle.String() != solved.String() {
+				// Naked singles become values, only complete puzzles round trip.
+				continue
+			}
+			height, width := puzzle.BoxDimensions()
+			out, err := Emit(puzzle, f)
+			require.NoError(t, err)
+
+			// Formats without box dimensions need them as options.
+			parsed, detected, err := Parse(out, sudoku.WithBoxDimensions(height, width))
+			require.NoError(t, err, "%s:\n%s", f, out)
+			require.Equal(t, f, detected, out)
+			require.Equal(t, puzzle.Arr, parsed.Arr, out)
+			parsedHeight, parsedWidth := parsed.BoxDimensions()
+			require.Equal(t, height, parsedHeight, out)
+			require.Equal(t, width, parsedWidth, out)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	testCases := []struct {
+		input  string
+		format Format
+	}{
+		{hardLine, Line},
+		{"\n\n" + rectLine + "\r\n", Line},
+		{"#A author\n1...\n....\n....\n....", SDK},
+		{"1...\n....\n....\n....\n", SDK},
+		{".---.\n| 1 |\n'---'", PencilMarks},
+		{"1   | 2   \n    |     ", Pretty},
+		{"    \n1 2 \n2 1 ", Pretty},
+	}
+	for _, tc := range testCases {
+		f, err := Detect(tc.input)
+		require.NoError(t, err, tc.input)
+		require.Equal(t, tc.format, f, tc.input)
+	}
+
+	_, err := Detect(" \n\t\n")
+	require.ErrorIs(t, err, ErrUnknownFormat)
+	_, _, err = Parse("")
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestEmitErrors(t *testing.T) {
+	arr := make([][]sudoku.PuzzleInt, 36)
+	for i := range arr {
+		arr[i] = make([]sudoku.PuzzleInt, 36)
+	}
+	puzzle, err := sudoku.NewPuzzle(arr)
+	require.NoError(t, err)
+	for _, f := range []Format{Line, SDK, PencilMarks} {
+		_, err := Emit(puzzle, f)
+		require.ErrorIs(t, err, ErrTooLarge, f.String())
+	}
+	// The pretty layout has no digit limit.
+	_, err = Emit(puzzle, Pretty)
+	require.NoError(t, err)
+
+	_, err = Emit(puzzle, Format(-1))
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestFormatText(t *testing.T) {
+	for f, name := range formatNames {
+		text, err := f.MarshalText()
+		require.NoError(t, err)
+		require.Equal(t, name, string(text))
+
+		var decoded Format
+		require.NoError(t, decoded.UnmarshalText(text))
+		require.Equal(t, f, decoded)
+	}
+	var f Format
+	require.ErrorIs(t, f.UnmarshalText([]byte("csv")), ErrUnknownFormat)
+}
+
+func TestParseError(t *testing.T) {
+	err := &ParseError{SDK, 3, 7, ErrSyntax}
+	require.Equal(t, "sdk format: line 3, column 7: invalid syntax", err.Error())
+	require.ErrorIs(t, err, ErrSyntax)
+}
@@ -0,0 +1,54 @@
+package format
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/husseinelguindi/sudoku-api/sudoku"
+)
+
+// parseLine parses the single line of the Line format, at the line first of the input.
+func parseLine(lines []string, first int, opts []sudoku.PuzzleOption) (sudoku.Puzzle, error) {
+	if len(lines) != 1 {
+		return sudoku.Puzzle{}, &ParseError{Line, first + 1, 1, fmt.Errorf("%w: expected a single line", ErrSyntax)}
+	}
+	line := lines[0]
+
+	// Check the positions ahead of sudoku.ParseLine, to report the column of invalid ones. The
+	// cages of Killer puzzles that follow them are left to sudoku.ParseLine.
+	cells := line
+	if i := strings.IndexByte(cells, ';'); i != -1 {
+		cells = cells[:i]
+	}
+	offset := strings.LastIndexByte(cells, ':') + 1
+	cells = cells[offset:]
+	side := int(math.Sqrt(float64(len(cells))))
+	if side*side != len(cells) {
+		// The side is unknown, leave reporting the length to sudoku.ParseLine.
+		side = maxSide
+	}
+	for i := 0; i < len(cells); i++ {
+		if _, err := cellValue(cells[i], side); err != nil {
+			return sudoku.Puzzle{}, &ParseError{Line, first, offset + i + 1, err}
+		}
+	}
+
+	puzzle, err := sudoku.ParseLine(line, opts...)
+	if err != nil {
+		return sudoku.Puzzle{}, &ParseError{Line, first, 1, err}
+	}
+	return puzzle, nil
+}
+
+// cellValue returns the value of the digit c of a position, in a puzzle of the passed side.
+func cellValue(c byte, side int) (sudoku.PuzzleInt, error) {
+	val, ok := digitValue(c)
+	if !ok {
+		return 0, fmt.Errorf("%w: invalid character %q", ErrSyntax, c)
+	}
+	if int(val) > side {
+		return 0, fmt.Errorf("%w: %d in a %dx%d puzzle", sudoku.ErrValueRange, val, side, side)
+	}
+	return val, nil
+}
@@ -0,0 +1,33 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/husseinelguindi/sudoku-api/sudoku"
+)
+
+func TestParseLineErrors(t *testing.T) {
+	testCases := []struct {
+		input        string
+		line, column int
+		err          error
+	}{
+		{"1..2.3.........#", 1, 16, ErrSyntax},
+		{"2x2:1..2.3.........#", 1, 20, ErrSyntax},
+		{"\n\n1..2.3......5...", 3, 13, sudoku.ErrValueRange},
+		{"1..2.3..........#", 1, 17, ErrSyntax},
+		{"1..2.3...........", 1, 1, sudoku.ErrLineFormat},
+		{"3x3:1..2.3..........", 1, 1, sudoku.ErrBoxDimensions},
+		{"1...\n....", 2, 1, ErrSyntax},
+		{"1..2.3.........#;0+1=3", 1, 16, ErrSyntax},
+		{"1..2.3..........;0+1", 1, 1, sudoku.ErrLineFormat},
+		{"1..2.3..........;0+1=2", 1, 1, sudoku.ErrCage},
+		{"0001201122312333:1..2.3.........#", 1, 33, ErrSyntax},
+		{"0001201122312334:1..2.3..........", 1, 1, sudoku.ErrRegions},
+		{"X:2x2:1..2.3.........#", 1, 22, ErrSyntax},
+	}
+	for _, tc := range testCases {
+		_, err := ParseAs(Line, tc.input)
+		requireParseError(t, err, tc.line, tc.column, tc.err)
+	}
+}
@@ -0,0 +1,193 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/husseinelguindi/sudoku-api/sudoku"
+)
+
+// Characters of the frame of the PencilMarks format.
+const (
+	pencilBorder = ".:'-+"
+	pencilBar    = '|'
+)
+
+// parsePencilMarks parses the lines of the PencilMarks format, the first of which is the line
+// first of the input. Rows are framed by bars, with a token of candidates per position, and
+// borders of dashes separate the bands of boxes. A token of a single digit is a value, while
+// longer tokens (or '.') are vacant positions. The box dimensions follow the frame.
+func parsePencilMarks(lines []string, first int, opts []sudoku.PuzzleOption) (sudoku.Puzzle, error) {
+	var rows [][]sudoku.PuzzleInt
+	boxHeight, boxWidth, band := 0, 0, 0
+	for i, line := range lines {
+		lineNo := first + i
+		if strings.Trim(line, pencilBorder) == "" && line != "" {
+			// A border, closing the band above it.
+			switch {
+			case i == 0:
+			case band == 0:
+				return sudoku.Puzzle{}, &ParseError{PencilMarks, lineNo, 1, fmt.Errorf("%w: empty band", ErrSyntax)}
+			case boxHeight == 0:
+				boxHeight = band
+			case band != boxHeight:
+				return sudoku.Puzzle{}, &ParseError{PencilMarks, lineNo, 1,
+					fmt.Errorf("%w: band of %d rows, expected %d", ErrSyntax, band, boxHeight)}
+			}
+			band = 0
+			continue
+		}
+		if i == 0 || len(line) < 2 || line[0] != pencilBar || line[len(line)-1] != pencilBar {
+			return sudoku.Puzzle{}, &ParseError{PencilMarks, lineNo, 1, fmt.Errorf("%w: expected a framed row or border", ErrSyntax)}
+		}
+
+		row, width, err := parsePencilRow(line)
+		if err != nil {
+			err.Line = lineNo
+			return sudoku.Puzzle{}, err
+		}
+		if boxWidth == 0 {
+			boxWidth = width
+		}
+		if len(rows) != 0 && (len(row) != len(rows[0]) || width != boxWidth) {
+			return sudoku.Puzzle{}, &ParseError{PencilMarks, lineNo, 1,
+				fmt.Errorf("%w: row of %d positions, unlike the first row", ErrSyntax, len(row))}
+		}
+		rows = append(rows, row)
+		band++
+	}
+	if band != 0 {
+		return sudoku.Puzzle{}, &ParseError{PencilMarks, first + len(lines) - 1, 1, fmt.Errorf("%w: missing bottom border", ErrSyntax)}
+	}
+
+	side := len(rows)
+	if side == 0 {
+		return sudoku.Puzzle{}, &ParseError{PencilMarks, first, 1, fmt.Errorf("%w: no rows", ErrSyntax)}
+	}
+	if len(rows[0]) != side {
+		return sudoku.Puzzle{}, &ParseError{PencilMarks, first, 1, fmt.Errorf("%w: %d rows of %d positions", ErrSyntax, side, len(rows[0]))}
+	}
+	// Check the values against the side, now that it is known.
+	for r, row := range rows {
+		for _, val := range row {
+			if int(val) > side {
+				return sudoku.Puzzle{}, &ParseError{PencilMarks, first, 1,
+					fmt.Errorf("%w: %d at row %d in a %dx%d puzzle", sudoku.ErrValueRange, val, r, side, side)}
+			}
+		}
+	}
+	return newPuzzle(PencilMarks, first, rows, boxHeight, boxWidth, opts)
+}
+
+// parsePencilRow parses the tokens of a framed row of the PencilMarks format, returning the
+// values of the row and the number of positions between bars.
+func parsePencilRow(line string) (row []sudoku.PuzzleInt, boxWidth int, err *ParseError) {
+	boxes := strings.Split(line[1:len(line)-1], string(pencilBar))
+	col := 2 // Column of the first character of the current box
+	for b, box := range boxes {
+		width := 0
+		for i := 0; i < len(box); {
+			if box[i] == ' ' {
+				i++
+				continue
+			}
+			// A token of digits, running up to the next space.
+			j := i
+			for j < len(box) && box[j] != ' ' {
+				if _, ok := digitValue(box[j]); !ok || (box[j] == '.' && j-i != 0) || (box[j] == '0') {
+					return nil, 0, &ParseError{Format: PencilMarks, Column: col + j, Err: fmt.Errorf("%w: invalid character %q", ErrSyntax, box[j])}
+				}
+				j++
+			}
+			val := sudoku.PuzzleInt(0)
+			if j-i == 1 {
+				val, _ = digitValue(box[i])
+			}
+			row = append(row, val)
+			width++
+			i = j
+		}
+		if b == 0 {
+			boxWidth = width
+		} else if width != boxWidth {
+			return nil, 0, &ParseError{Format: PencilMarks, Column: col, Err: fmt.Errorf("%w: box of %d positions, expected %d", ErrSyntax, width, boxWidth)}
+		}
+		col += len(box) + 1
+	}
+	return row, boxWidth, nil
+}
+
+// emitPencilMarks returns the puzzle in the PencilMarks format, listing the candidates of every
+// vacant position (see sudoku.Puzzle.Candidates). Columns are padded to their widest token.
+func emitPencilMarks(p sudoku.Puzzle) (string, error) {
+	side := len(p.Arr)
+	if side > maxSide {
+		return "", fmt.Errorf("%w: side %d", ErrTooLarge, side)
+	}
+	boxHeight, boxWidth := p.BoxDimensions()
+
+	// The token of every position, and the width of every column.
+	tokens := make([][]string, side)
+	widths := make([]int, side)
+	for row := range p.Arr {
+		tokens[row] = make([]string, side)
+		for col, val := range p.Arr[row] {
+			var token []byte
+			if val != 0 {
+				token = []byte{digit(val)}
+			}
+			for _, c := range p.Candidates(sudoku.PuzzleInt(row), sudoku.PuzzleInt(col)) {
+				token = append(token, digit(c))
+			}
+			if len(token) == 0 {
+				// A vacant position without candidates.
+				token = []byte{'.'}
+			}
+			tokens[row][col] = string(token)
+			if len(token) > widths[col] {
+				widths[col] = len(token)
+			}
+		}
+	}
+
+	// border returns a border, with end at the ends and middle between boxes. Boxes are as wide
+	// as their tokens, with two spaces between tokens and a space at either edge.
+	border := func(end, middle byte) string {
+		var sb strings.Builder
+		sb.WriteByte(end)
+		for box := 0; box < side/int(boxWidth); box++ {
+			if box != 0 {
+				sb.WriteByte(middle)
+			}
+			n := 2 * int(boxWidth)
+			for col := box * int(boxWidth); col < (box+1)*int(boxWidth); col++ {
+				n += widths[col]
+			}
+			sb.WriteString(strings.Repeat("-", n))
+		}
+		sb.WriteByte(end)
+		return sb.String()
+	}
+
+	var sb strings.Builder
+	sb.WriteString(border('.', '.') + "\n")
+	for row := range tokens {
+		if row != 0 && row%int(boxHeight) == 0 {
+			sb.WriteString(border(':', '+') + "\n")
+		}
+		for col, token := range tokens[row] {
+			if col%int(boxWidth) == 0 {
+				sb.WriteString("| ")
+			}
+			sb.WriteString(token + strings.Repeat(" ", widths[col]-len(token)))
+			if col%int(boxWidth) == int(boxWidth)-1 {
+				sb.WriteByte(' ')
+			} else {
+				sb.WriteString("  ")
+			}
+		}
+		sb.WriteString("|\n")
+	}
+	sb.WriteString(border('\'', '\'') + "\n")
+	return sb.String(), nil
+}
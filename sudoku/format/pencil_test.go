@@ -0,0 +1,81 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/husseinelguindi/sudoku-api/sudoku"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePencilMarks(t *testing.T) {
+	// As exported by HoDoKu, with candidates of every vacant position.
+	puzzle, f, err := Parse(`
+.-------------------.-------------.
+| 5     1      36   | 346  2  46  |
+| 236   236    4    | 1356 35 16  |
+:-------------------+-------------:
+| 1346  3456   2    | 134  34 14  |
+| 134   34     13   | 1234 6  5   |
+:-------------------+-------------:
+| 12346 2346   5    | 246  4  246 |
+| 246   246    .    | 2456 1  3   |
+'-------------------'-------------'
+`)
+	require.NoError(t, err)
+	require.Equal(t, PencilMarks, f)
+	require.Equal(t, [][]sudoku.PuzzleInt{
+		{5, 1, 0, 0, 2, 0},
+		{0, 0, 4, 0, 0, 0},
+		{0, 0, 2, 0, 0, 0},
+		{0, 0, 0, 0, 6, 5},
+		{0, 0, 5, 0, 4, 0},
+		{0, 0, 0, 0, 1, 3},
+	}, puzzle.Arr)
+	height, width := puzzle.BoxDimensions()
+	require.Equal(t, sudoku.PuzzleInt(2), height)
+	require.Equal(t, sudoku.PuzzleInt(3), width)
+}
+
+func TestEmitPencilMarks(t *testing.T) {
+	puzzle := mustParseLine(t, "1..2.3..........")
+	out, err := Emit(puzzle, PencilMarks)
+	require.NoError(t, err)
+	require.Equal(t, `.----------.-----------.
+| 1    4   | 34    2   |
+| 24   3   | 14    14  |
+:----------+-----------:
+| 234  124 | 1234  134 |
+| 234  124 | 1234  134 |
+'----------'-----------'
+`, out)
+}
+
+func TestParsePencilMarksErrors(t *testing.T) {
+	testCases := []struct {
+		input        string
+		line, column int
+		err          error
+	}{
+		// Missing bottom border.
+		{".---.---.\n| 1 | 2 |\n| 2 | 1 |", 3, 1, ErrSyntax},
+		// Unframed row.
+		{".---.---.\n| 1 | 2 \n| 2 | 1 |\n'---'---'", 2, 1, ErrSyntax},
+		// Invalid candidate.
+		{".---.---.\n| 1 | 2 |\n| 2 | 1x? |\n'---'---'", 3, 9, ErrSyntax},
+		// Uneven boxes.
+		{".---.---.\n| 1 | 2 |\n| 2 1 | |\n'---'---'", 3, 8, ErrSyntax},
+		// Uneven bands.
+		{".---.---.\n| 1 | 2 |\n:---+---:\n| 2 | 1 |\n| . | . |\n'---'---'", 6, 1, ErrSyntax},
+		// A lone bar, with or without trailing space.
+		{".-\n|", 2, 1, ErrSyntax},
+		{".-\n| ", 2, 1, ErrSyntax},
+		// Only a border.
+		{".-------.", 1, 1, ErrSyntax},
+		// Values beyond the side.
+		{".---.---.\n| 1 | 3 |\n| 2 | 1 |\n'---'---'", 1, 1, sudoku.ErrValueRange},
+	}
+	for _, tc := range testCases {
+		_, err := ParseAs(PencilMarks, tc.input)
+		requireParseError(t, err, tc.line, tc.column, tc.err)
+	}
+}
@@ -0,0 +1,131 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/husseinelguindi/sudoku-api/sudoku"
+)
+
+// parsePretty parses the lines of the Pretty format, the first of which is the line first of the
+// input. Rows hold a decimal value or a blank per position, each followed by a space, with "| "
+// between boxes. Lines of "- " separate the bands of boxes. The box dimensions follow the
+// separators. As the trailing whitespace of rows is often lost when copied, short rows end with
+// blanks. Positions followed by a '*' instead of a space mark the diagonals of X-Sudoku puzzles,
+// which are then parsed with sudoku.WithDiagonals.
+func parsePretty(lines []string, first int, opts []sudoku.PuzzleOption) (sudoku.Puzzle, error) {
+	// Split the rows from the separators, measuring the box height.
+	var rows []int // Index of the line of each row
+	boxHeight := 0
+	for i, line := range lines {
+		if strings.Trim(line, "- ") != "" {
+			rows = append(rows, i)
+			continue
+		}
+		if line == "" || (boxHeight == 0 && len(rows) == 0) {
+			return sudoku.Puzzle{}, &ParseError{Pretty, first + i, 1, fmt.Errorf("%w: unexpected separator", ErrSyntax)}
+		}
+		if boxHeight == 0 {
+			boxHeight = len(rows)
+		}
+		if len(rows)%boxHeight != 0 || (i > 0 && strings.Trim(lines[i-1], "- ") == "") {
+			return sudoku.Puzzle{}, &ParseError{Pretty, first + i, 1,
+				fmt.Errorf("%w: separator after %d rows, expected every %d", ErrSyntax, len(rows), boxHeight)}
+		}
+	}
+	if rows[len(rows)-1] != len(lines)-1 {
+		return sudoku.Puzzle{}, &ParseError{Pretty, first + len(lines) - 1, 1, fmt.Errorf("%w: unexpected separator", ErrSyntax)}
+	}
+	side := len(rows)
+	if boxHeight == 0 {
+		boxHeight = side
+	}
+	if side%boxHeight != 0 {
+		return sudoku.Puzzle{}, &ParseError{Pretty, first + len(lines) - 1, 1,
+			fmt.Errorf("%w: %d rows after the last separator, expected %d", ErrSyntax, side%boxHeight, boxHeight)}
+	}
+
+	// The box width follows the bars of the rows, where rows with fewer bars are malformed.
+	bars := 0
+	for _, i := range rows {
+		if n := strings.Count(lines[i], "|"); n > bars {
+			bars = n
+		}
+	}
+	if side%(bars+1) != 0 {
+		return sudoku.Puzzle{}, &ParseError{Pretty, first, 1, fmt.Errorf("%w: %d boxes in a row of %d", ErrSyntax, bars+1, side)}
+	}
+	boxWidth := side / (bars + 1)
+	arr := newMatrix(side)
+	marks := make([][]bool, side)
+	diagonals := false
+	for row, i := range rows {
+		marks[row] = make([]bool, side)
+		if err := parsePrettyRow(arr[row], marks[row], lines[i], boxWidth); err != nil {
+			err.Line = first + i
+			return sudoku.Puzzle{}, err
+		}
+		diagonals = diagonals || marks[row][row] || marks[row][side-1-row]
+	}
+
+	// Either every position of the diagonals is marked, or none are.
+	if diagonals {
+		for row, i := range rows {
+			for col, marked := range marks[row] {
+				if marked != (col == row || col == side-1-row) {
+					return sudoku.Puzzle{}, &ParseError{Pretty, first + i, 1,
+						fmt.Errorf("%w: marked positions are not the diagonals", ErrSyntax)}
+				}
+			}
+		}
+		opts = append(opts, sudoku.WithDiagonals())
+	}
+	return newPuzzle(Pretty, first, arr, boxHeight, boxWidth, opts)
+}
+
+// parsePrettyRow parses the positions of a row of the Pretty format into row, and their diagonal
+// marks into marks, returning the column of the first error.
+func parsePrettyRow(row []sudoku.PuzzleInt, marks []bool, line string, boxWidth int) *ParseError {
+	col := 0
+	for i := 0; i < len(line); {
+		switch {
+		case line[i] == '|':
+			if col == 0 || col%boxWidth != 0 || col == len(row) {
+				return &ParseError{Format: Pretty, Column: i + 1, Err: fmt.Errorf("%w: unexpected bar", ErrSyntax)}
+			}
+			i += 2
+			continue
+		case col == len(row):
+			return &ParseError{Format: Pretty, Column: i + 1, Err: fmt.Errorf("%w: more than %d positions", ErrSyntax, len(row))}
+		case col != 0 && col%boxWidth == 0 && line[i-2] != '|':
+			return &ParseError{Format: Pretty, Column: i + 1, Err: fmt.Errorf("%w: expected a bar", ErrSyntax)}
+		case line[i] == ' ':
+			// A blank position, followed by a space, a mark, or the end of the line.
+			if i+1 < len(line) && line[i+1] != ' ' && line[i+1] != '*' {
+				return &ParseError{Format: Pretty, Column: i + 2, Err: fmt.Errorf("%w: invalid character %q", ErrSyntax, line[i+1])}
+			}
+			marks[col] = i+1 < len(line) && line[i+1] == '*'
+			i += 2
+		default:
+			// A decimal value, followed by a space, a mark, or the end of the line.
+			j := i
+			for j < len(line) && line[j] >= '0' && line[j] <= '9' {
+				j++
+			}
+			if j == i || (j < len(line) && line[j] != ' ' && line[j] != '*') {
+				return &ParseError{Format: Pretty, Column: j + 1, Err: fmt.Errorf("%w: invalid character %q", ErrSyntax, line[j])}
+			}
+			val, err := strconv.Atoi(line[i:j])
+			if err != nil || val == 0 || val > len(row) {
+				return &ParseError{Format: Pretty, Column: i + 1,
+					Err: fmt.Errorf("%w: %d in a %dx%d puzzle", sudoku.ErrValueRange, val, len(row), len(row))}
+			}
+			row[col] = sudoku.PuzzleInt(val)
+			marks[col] = j < len(line) && line[j] == '*'
+			i = j + 1
+		}
+		col++
+	}
+	return nil
+}
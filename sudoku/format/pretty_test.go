@@ -0,0 +1,73 @@
+package format
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/husseinelguindi/sudoku-api/sudoku"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePretty(t *testing.T) {
+	puzzle := mustParseLine(t, rectLine)
+
+	// Trailing whitespace is often lost when copying.
+	var trimmed []string
+	for _, line := range strings.Split(puzzle.Pretty(), "\n") {
+		trimmed = append(trimmed, strings.TrimRight(line, " "))
+	}
+	parsed, f, err := Parse(strings.Join(trimmed, "\n"))
+	require.NoError(t, err)
+	require.Equal(t, Pretty, f)
+	require.Equal(t, puzzle.Arr, parsed.Arr)
+	height, width := parsed.BoxDimensions()
+	require.Equal(t, sudoku.PuzzleInt(2), height)
+	require.Equal(t, sudoku.PuzzleInt(3), width)
+}
+
+func TestParsePrettyDiagonals(t *testing.T) {
+	puzzle, err := sudoku.ParseLine("1..4............", sudoku.WithDiagonals())
+	require.NoError(t, err)
+	text := puzzle.Pretty()
+	require.Contains(t, text, "*")
+
+	parsed, f, err := Parse(text)
+	require.NoError(t, err)
+	require.Equal(t, Pretty, f)
+	require.Equal(t, puzzle.Arr, parsed.Arr)
+	require.Equal(t, text, parsed.Pretty())
+	// The diagonals are constraints of the parsed puzzle, ruling out the 1 of the top left.
+	require.NotContains(t, parsed.Candidates(3, 3), sudoku.PuzzleInt(1))
+}
+
+func TestParsePrettyErrors(t *testing.T) {
+	testCases := []struct {
+		input        string
+		line, column int
+		err          error
+	}{
+		// Separator before any row.
+		{"- - - - -\n1   | 2   \n    |     ", 1, 1, ErrSyntax},
+		// Separator at the end.
+		{"1   | 2   \n- - - - -", 2, 1, ErrSyntax},
+		// Bands of different heights.
+		{"1   |     \n- - - - -\n    |     \n    |     \n- - - - -", 5, 1, ErrSyntax},
+		// Bar within a box.
+		{"1 | 2 3 4\n    |     \n    |     \n    |     ", 1, 3, ErrSyntax},
+		// Missing bar.
+		{"1 2 3 4\n    |     \n    |     \n    |     ", 1, 5, ErrSyntax},
+		// Invalid value.
+		{"1 x | 2 \n    |     \n    |     \n    |     ", 1, 3, ErrSyntax},
+		{"1 5 | 2 \n    |     \n    |     \n    |     ", 1, 3, sudoku.ErrValueRange},
+		// Marks off the diagonals.
+		{"1*  |   4*\n   *|  *  \n    |  *  \n4*  |   1*", 3, 1, ErrSyntax},
+		// Invalid mark.
+		{"1   | 2   \n +  |     \n    |     \n    |     ", 2, 2, ErrSyntax},
+		// Too many positions.
+		{"1   | 2   4\n    |     \n    |     \n    |     ", 1, 11, ErrSyntax},
+	}
+	for _, tc := range testCases {
+		_, err := ParseAs(Pretty, tc.input)
+		requireParseError(t, err, tc.line, tc.column, tc.err)
+	}
+}
@@ -0,0 +1,61 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/husseinelguindi/sudoku-api/sudoku"
+)
+
+// parseSDK parses the lines of the SDK format, the first of which is the line first of the input.
+// Metadata lines, starting with '#', are skipped.
+func parseSDK(lines []string, first int, opts []sudoku.PuzzleOption) (sudoku.Puzzle, error) {
+	for len(lines) != 0 && strings.HasPrefix(lines[0], "#") {
+		lines = lines[1:]
+		first++
+	}
+	side := len(lines)
+	if side == 0 {
+		return sudoku.Puzzle{}, &ParseError{SDK, first, 1, fmt.Errorf("%w: no rows after the metadata", ErrSyntax)}
+	}
+	if side > maxSide {
+		return sudoku.Puzzle{}, &ParseError{SDK, first, 1, fmt.Errorf("%w: %d rows", ErrTooLarge, side)}
+	}
+
+	// Every row has a digit per position.
+	arr := newMatrix(side)
+	for row, line := range lines {
+		if len(line) != side {
+			col := len(line) + 1
+			if col > side {
+				col = side + 1
+			}
+			return sudoku.Puzzle{}, &ParseError{SDK, first + row, col,
+				fmt.Errorf("%w: %d positions in a row of %d", ErrSyntax, len(line), side)}
+		}
+		for col := 0; col < side; col++ {
+			val, err := cellValue(line[col], side)
+			if err != nil {
+				return sudoku.Puzzle{}, &ParseError{SDK, first + row, col + 1, err}
+			}
+			arr[row][col] = val
+		}
+	}
+	return newPuzzle(SDK, first, arr, 0, 0, opts)
+}
+
+// emitSDK returns the puzzle in the SDK format, without metadata. Box dimensions are not part of
+// the format.
+func emitSDK(p sudoku.Puzzle) (string, error) {
+	if len(p.Arr) > maxSide {
+		return "", fmt.Errorf("%w: side %d", ErrTooLarge, len(p.Arr))
+	}
+	var sb strings.Builder
+	for _, row := range p.Arr {
+		for _, val := range row {
+			sb.WriteByte(digit(val))
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,50 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/husseinelguindi/sudoku-api/sudoku"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSDK(t *testing.T) {
+	puzzle, f, err := Parse(`#A SadMan Software
+#D A 4x4 puzzle
+#L Easy
+1..2
+.3..
+....
+...4
+`)
+	require.NoError(t, err)
+	require.Equal(t, SDK, f)
+	require.Equal(t, [][]sudoku.PuzzleInt{
+		{1, 0, 0, 2},
+		{0, 3, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 4},
+	}, puzzle.Arr)
+
+	out, err := Emit(puzzle, SDK)
+	require.NoError(t, err)
+	require.Equal(t, "1..2\n.3..\n....\n...4\n", out)
+}
+
+func TestParseSDKErrors(t *testing.T) {
+	testCases := []struct {
+		input        string
+		line, column int
+		err          error
+	}{
+		{"#A author\n#D description", 3, 1, ErrSyntax},
+		{"1..2\n.3.\n....\n....", 2, 4, ErrSyntax},
+		{"1..2\n.3...\n....\n....", 2, 5, ErrSyntax},
+		{"1..2\n.3..\n..x.\n....", 3, 3, sudoku.ErrValueRange},
+		{"#A author\n1..2\n.3..\n..?.\n....", 4, 3, ErrSyntax},
+		{"1..2\n\n.3..\n....", 2, 1, ErrSyntax},
+	}
+	for _, tc := range testCases {
+		_, err := ParseAs(SDK, tc.input)
+		requireParseError(t, err, tc.line, tc.column, tc.err)
+	}
+}
@@ -0,0 +1,217 @@
+package sudoku
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Difficulty represents a band of puzzle difficulty.
+type Difficulty int
+
+// Difficulty bands, from easiest to hardest.
+const (
+	Easy Difficulty = iota
+	Medium
+	Hard
+	Expert
+)
+
+// difficultyNames maps a Difficulty to its name.
+var difficultyNames = map[Difficulty]string{
+	Easy:   "easy",
+	Medium: "medium",
+	Hard:   "hard",
+	Expert: "expert",
+}
+
+// String implements the Stringer interface for Difficulty.
+func (d Difficulty) String() string {
+	if name, ok := difficultyNames[d]; ok {
+		return name
+	}
+	return fmt.Sprintf("Difficulty(%d)", int(d))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for Difficulty, encoding it by name.
+func (d Difficulty) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for Difficulty, decoding it by name.
+func (d *Difficulty) UnmarshalText(text []byte) error {
+	for diff, name := range difficultyNames {
+		if name == string(text) {
+			*d = diff
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown difficulty %q", text)
+}
+
+// clueRatios maps a Difficulty to the ratio of the puzzle cells that are kept as clues once the
+// difficulty is reached, which keeps easier puzzles from becoming sparse.
+var clueRatios = map[Difficulty]float64{
+	Easy:   0.45,
+	Medium: 0.40,
+	Hard:   0.30,
+	Expert: 0,
+}
+
+// maxGenerateAttempts is the number of complete grids a puzzle is generated from, at most, when
+// trying to reach a difficulty band.
+const maxGenerateAttempts = 20
+
+// ErrDifficulty is returned when generating a puzzle of an unknown difficulty.
+var ErrDifficulty = errors.New("unknown difficulty")
+
+// generateConfig represents the configuration of a puzzle generator.
+type generateConfig struct {
+	rand *rand.Rand
+}
+
+// GenerateOption represents a function that, when called, configures a puzzle generator.
+type GenerateOption func(*generateConfig)
+
+// WithRand sets the source of randomness of the generator. Generating with identically seeded
+// sources produces identical puzzles.
+func WithRand(r *rand.Rand) GenerateOption {
+	return func(c *generateConfig) {
+		c.rand = r
+	}
+}
+
+// WithSeed seeds the source of randomness of the generator.
+func WithSeed(seed int64) GenerateOption {
+	return WithRand(rand.New(rand.NewSource(seed)))
+}
+
+// Generate creates a random puzzle with a unique solution, made of boxHeight x boxWidth boxes.
+// The side length of the puzzle is the product of the box dimensions. A random complete grid is
+// built, then clues are removed in a random order, as long as the solution stays unique and the
+// rating (see Rate) does not exceed the difficulty band. Removal stops once the band is reached
+// and the clues are down to the ratio of the band. If the band is not reached, generation is
+// retried with another grid, returning the hardest puzzle found after maxGenerateAttempts.
+//
+// The time taken grows quickly with the side length, as every removal is checked for uniqueness.
+func Generate(boxHeight, boxWidth PuzzleInt, difficulty Difficulty, opts ...GenerateOption) (Puzzle, error) {
+	ratio, ok := clueRatios[difficulty]
+	if !ok {
+		return Puzzle{}, fmt.Errorf("%w: %d", ErrDifficulty, int(difficulty))
+	}
+	side := int(boxHeight) * int(boxWidth)
+	if side == 0 {
+		return Puzzle{}, fmt.Errorf("%w: %dx%d boxes", ErrBoxDimensions, boxHeight, boxWidth)
+	}
+	if side > math.MaxUint16 {
+		return Puzzle{}, fmt.Errorf("%w: side %d is larger than %d", ErrTooLarge, side, math.MaxUint16)
+	}
+
+	// Apply options.
+	config := generateConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.rand == nil {
+		config.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	var best Puzzle
+	var bestRating Rating
+	minClues := int(math.Ceil(ratio * float64(side*side)))
+	for attempt := 0; attempt < maxGenerateAttempts; attempt++ {
+		puzzle, rating, err := generate(config.rand, boxHeight, boxWidth, difficulty, minClues)
+		if err != nil {
+			return Puzzle{}, err
+		}
+		if rating.Difficulty == difficulty {
+			return puzzle, nil
+		}
+		if attempt == 0 || rating.Score > bestRating.Score {
+			best, bestRating = puzzle, rating
+		}
+	}
+	return best, nil
+}
+
+// generate creates a puzzle from a single random complete grid, removing clues while the
+// solution stays unique and the rating does not exceed difficulty, until the difficulty is
+// reached with at most minClues clues. The rating of the puzzle is returned with it.
+func generate(r *rand.Rand, boxHeight, boxWidth PuzzleInt, difficulty Difficulty, minClues int) (Puzzle, Rating, error) {
+	side := int(boxHeight) * int(boxWidth)
+	puzzle, err := NewPuzzle(randomGrid(r, boxHeight, boxWidth), WithBoxDimensions(boxHeight, boxWidth))
+	if err != nil {
+		return Puzzle{}, Rating{}, err
+	}
+	rating, err := puzzle.Rate()
+	if err != nil {
+		return Puzzle{}, Rating{}, err
+	}
+
+	// Remove clues in a random order, restoring those that make the solution ambiguous or the
+	// puzzle too hard.
+	clues := side * side
+	for _, i := range r.Perm(side * side) {
+		if clues <= minClues && rating.Difficulty == difficulty {
+			break
+		}
+		row, col := i/side, i%side
+		val := puzzle.Arr[row][col]
+
+		// Vacate the position, resetting the bitsets.
+		puzzle.unset(PuzzleInt(row), PuzzleInt(col), val)
+		if puzzle.HasUniqueSolution() {
+			if next, err := puzzle.Rate(); err == nil && next.Difficulty <= difficulty {
+				clues--
+				rating = next
+				continue
+			}
+		}
+		puzzle.set(PuzzleInt(row), PuzzleInt(col), val)
+	}
+	return puzzle, rating, nil
+}
+
+// randomGrid returns a random complete grid made of boxHeight x boxWidth boxes. A valid pattern
+// grid is shuffled by transformations that preserve validity: relabelling digits, permuting rows
+// within a band, bands, columns within a stack, stacks, and transposing square boxes.
+func randomGrid(r *rand.Rand, boxHeight, boxWidth PuzzleInt) [][]PuzzleInt {
+	h, w := int(boxHeight), int(boxWidth)
+	side := h * w
+
+	// Random row and column orders, permuting bands (stacks) and rows (columns) within them.
+	rows := shuffledLines(r, side/h, h)
+	cols := shuffledLines(r, side/w, w)
+	digits := r.Perm(side)
+	transpose := h == w && r.Intn(2) == 1
+
+	grid := make([][]PuzzleInt, side)
+	for i := range grid {
+		grid[i] = make([]PuzzleInt, side)
+		for j := range grid[i] {
+			row, col := rows[i], cols[j]
+			if transpose {
+				row, col = rows[j], cols[i]
+			}
+			// Pattern of a valid grid, shifting each row by the box width and each band by one.
+			pattern := (w*(row%h) + row/h + col) % side
+			grid[i][j] = PuzzleInt(digits[pattern] + 1)
+		}
+	}
+	return grid
+}
+
+// shuffledLines returns a random order of the lines (rows or columns) of a puzzle with the passed
+// number of groups (bands or stacks) of size lines each. Lines are only moved within their group,
+// and groups are moved as a whole.
+func shuffledLines(r *rand.Rand, groups, size int) []int {
+	lines := make([]int, 0, groups*size)
+	for _, g := range r.Perm(groups) {
+		for _, l := range r.Perm(size) {
+			lines = append(lines, g*size+l)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,81 @@
+package sudoku
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countClues returns the number of occupied positions of the puzzle.
+func countClues(p Puzzle) int {
+	var n int
+	for _, row := range p.Arr {
+		for _, v := range row {
+			if v != 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func TestGenerate(t *testing.T) {
+	testCases := []struct {
+		boxHeight, boxWidth PuzzleInt
+		difficulty          Difficulty
+	}{
+		{2, 2, Easy},
+		{2, 2, Expert},
+		{2, 3, Medium},
+		{3, 2, Hard},
+		{3, 3, Easy},
+		{3, 3, Medium},
+		{3, 3, Hard},
+	}
+	for _, tc := range testCases {
+		puzzle, err := Generate(tc.boxHeight, tc.boxWidth, tc.difficulty, WithSeed(1))
+		require.NoError(t, err)
+
+		side := int(tc.boxHeight * tc.boxWidth)
+		require.Len(t, puzzle.Arr, side)
+		require.Empty(t, puzzle.Validate())
+		require.True(t, puzzle.HasUniqueSolution())
+		// The puzzle never exceeds the difficulty.
+		rating, err := puzzle.Rate()
+		require.NoError(t, err)
+		require.LessOrEqual(t, int(rating.Difficulty), int(tc.difficulty))
+		// Complete grids are easy, so easy puzzles only have clues removed down to their ratio.
+		if tc.difficulty == Easy {
+			require.Equal(t, int(math.Ceil(clueRatios[Easy]*float64(side*side))), countClues(puzzle))
+		}
+
+		// The same seed generates the same puzzle.
+		again, err := Generate(tc.boxHeight, tc.boxWidth, tc.difficulty, WithSeed(1))
+		require.NoError(t, err)
+		require.Equal(t, puzzle.Arr, again.Arr)
+
+		require.True(t, puzzle.Solve())
+		require.Empty(t, puzzle.Validate())
+	}
+}
+
+func TestRandomGrid(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		for _, dims := range [][2]PuzzleInt{{2, 2}, {2, 3}, {3, 3}, {2, 4}, {4, 4}, {5, 5}} {
+			grid := randomGrid(rand.New(rand.NewSource(seed)), dims[0], dims[1])
+			puzzle, err := NewPuzzle(grid, WithBoxDimensions(dims[0], dims[1]))
+			require.NoError(t, err)
+			require.Empty(t, puzzle.Validate())
+			require.Equal(t, len(grid)*len(grid), countClues(puzzle))
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := Generate(0, 3, Easy)
+	require.ErrorIs(t, err, ErrBoxDimensions)
+	_, err = Generate(3, 3, Difficulty(-1))
+	require.ErrorIs(t, err, ErrDifficulty)
+}
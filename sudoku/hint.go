@@ -0,0 +1,70 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Errors returned when a hint cannot be given.
+var (
+	ErrSolved         = errors.New("puzzle is already solved")
+	ErrNoHint         = errors.New("no logical deduction found")
+	ErrOutOfBounds    = errors.New("cell is out of the puzzle bounds")
+	ErrBadElimination = errors.New("eliminated candidate is the solution")
+)
+
+// Hint returns the next deduction a person could make from the values of the puzzle, which is
+// the step the logical solver (see SolveLogical) would take first. The candidates a player has
+// already eliminated may be passed, so that the same eliminations are not hinted again.
+//
+// Hint returns ErrUnsolvable if the values of the puzzle conflict or lead to no solution,
+// ErrSolved if the puzzle is complete, ErrBadElimination if a candidate eliminated by the player
+// is the solution of its position, and ErrNoHint if no technique applies. The puzzle is left
+// untouched.
+func (p Puzzle) Hint(eliminated ...Candidate) (Step, error) {
+	return p.HintContext(context.Background(), eliminated...)
+}
+
+// HintContext returns the next deduction of the puzzle like Hint, stopping the search for a
+// solution once ctx is done or the budget of the puzzle is exceeded (see WithMaxNodes and
+// WithMaxDuration), in which case an error wrapping ErrBudgetExceeded or the error of ctx is
+// returned. The solution is searched in MinRemainingValues order, whatever the search order of
+// the puzzle, as large puzzles are out of reach of RowMajor. The techniques are stopped once ctx
+// is done as well. For puzzles with several solutions, eliminations are checked against the
+// first solution found.
+func (p Puzzle) HintContext(ctx context.Context, eliminated ...Candidate) (Step, error) {
+	if len(p.Validate()) != 0 {
+		return Step{}, ErrUnsolvable
+	}
+	mrv := p
+	WithSearchOrder(MinRemainingValues)(&mrv)
+	solution, _, err := mrv.SolveCopy(ctx)
+	if err != nil {
+		return Step{}, err
+	}
+
+	s := newLogicalSolver(p)
+	if s.solved() {
+		return Step{}, ErrSolved
+	}
+	// Apply the eliminations of the player, which must leave the solution possible.
+	for _, c := range eliminated {
+		if int(c.Row) >= s.side || int(c.Col) >= s.side {
+			return Step{}, fmt.Errorf("%w: row %d, column %d", ErrOutOfBounds, c.Row, c.Col)
+		}
+		if solution.Arr[c.Row][c.Col] == c.Value {
+			return Step{}, fmt.Errorf("%w: %d at row %d, column %d", ErrBadElimination, c.Value, c.Row, c.Col)
+		}
+		s.eliminate(nil, s.index(c.Cell), c.Value)
+	}
+
+	step, ok, err := s.nextContext(ctx)
+	if err != nil {
+		return Step{}, err
+	}
+	if !ok {
+		return Step{}, ErrNoHint
+	}
+	return step, nil
+}
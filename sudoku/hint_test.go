@@ -0,0 +1,154 @@
+package sudoku
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHint(t *testing.T) {
+	puzzle, err := NewPuzzle([][]PuzzleInt{
+		{1, 2, 3, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	})
+	require.NoError(t, err)
+
+	step, err := puzzle.Hint()
+	require.NoError(t, err)
+	require.Equal(t, Step{
+		Technique: NakedSingle,
+		Placement: &Candidate{Cell{0, 3}, 4},
+		Cells:     []Cell{{0, 0}, {0, 1}, {0, 2}},
+	}, step)
+	// The puzzle is untouched.
+	require.Zero(t, puzzle.Arr[0][3])
+}
+
+func TestHintErrors(t *testing.T) {
+	testCases := []struct {
+		arr        [][]PuzzleInt
+		eliminated []Candidate
+		err        error
+	}{
+		{
+			// Conflicting values.
+			arr: [][]PuzzleInt{
+				{1, 1, 0, 0},
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+			},
+			err: ErrUnsolvable,
+		},
+		{
+			// Values without conflicts, but without a solution.
+			arr: [][]PuzzleInt{
+				{1, 2, 0, 0},
+				{0, 0, 3, 4},
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+			},
+			err: ErrUnsolvable,
+		},
+		{
+			arr: [][]PuzzleInt{
+				{1, 2, 3, 4},
+				{3, 4, 1, 2},
+				{2, 1, 4, 3},
+				{4, 3, 2, 1},
+			},
+			err: ErrSolved,
+		},
+		{
+			arr:        emptyArr(4),
+			eliminated: []Candidate{{Cell{4, 0}, 1}},
+			err:        ErrOutOfBounds,
+		},
+		{
+			// Eliminating the solution, which would otherwise hint a naked single of 2.
+			arr:        lineArr("53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79", 9),
+			eliminated: []Candidate{{Cell{0, 3}, 6}},
+			err:        ErrBadElimination,
+		},
+	}
+	for _, tc := range testCases {
+		puzzle, err := NewPuzzle(tc.arr)
+		require.NoError(t, err)
+
+		_, err = puzzle.Hint(tc.eliminated...)
+		require.ErrorIs(t, err, tc.err)
+	}
+}
+
+func TestHintBudget(t *testing.T) {
+	puzzle, err := NewPuzzle(boxlessArr(), WithMaxNodes(1000))
+	require.NoError(t, err)
+	_, err = puzzle.HintContext(context.Background())
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = puzzle.HintContext(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestHintLarge(t *testing.T) {
+	// A 64x64 puzzle, with a quarter of a patterned solution vacated, which RowMajor takes millions
+	// of nodes to solve.
+	const side, box = 64, 8
+	arr := emptyArr(side)
+	for row := range arr {
+		for col := range arr[row] {
+			if (row*7+col*13)%4 != 0 {
+				arr[row][col] = PuzzleInt((box*(row%box)+row/box+col)%side + 1)
+			}
+		}
+	}
+	puzzle, err := NewPuzzle(arr, WithMaxNodes(1000))
+	require.NoError(t, err)
+	_, err = puzzle.HintContext(context.Background())
+	require.NoError(t, err)
+
+	// The techniques stop once ctx is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = newLogicalSolver(puzzle).nextContext(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+// TestHintSolve solves a puzzle by following hints only, passing back the hinted eliminations.
+func TestHintSolve(t *testing.T) {
+	const line = "100000569492056108056109240009640801064010000218035604040500016905061402621000005"
+	arr := lineArr(line, 9)
+
+	var eliminated []Candidate
+	var hasElims bool
+	for i := 0; ; i++ {
+		require.Less(t, i, 81*9, "hints do not progress")
+
+		puzzle, err := NewPuzzle(arr)
+		require.NoError(t, err)
+		step, err := puzzle.Hint(eliminated...)
+		if err == ErrSolved {
+			break
+		}
+		require.NoError(t, err)
+		require.NotEqual(t, Guess, step.Technique)
+
+		if step.Placement != nil {
+			arr[step.Placement.Row][step.Placement.Col] = step.Placement.Value
+		}
+		hasElims = hasElims || len(step.Eliminations) != 0
+		eliminated = append(eliminated, step.Eliminations...)
+	}
+	require.True(t, hasElims)
+
+	// The hinted values match the solution.
+	puzzle, err := NewPuzzle(lineArr(line, 9))
+	require.NoError(t, err)
+	require.True(t, puzzle.Solve())
+	require.Equal(t, puzzle.Arr, arr)
+}
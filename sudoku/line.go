@@ -0,0 +1,266 @@
+package sudoku
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrLineFormat is returned when a puzzle cannot be parsed from, or encoded to, the line format.
+var ErrLineFormat = errors.New("malformed puzzle line")
+
+// maxLineSide is the largest side of a puzzle in the line format, where every value is a single
+// base-36 digit.
+const maxLineSide = 35
+
+// diagonalsFlag is the prefix of the line format marking X-Sudoku puzzles.
+const diagonalsFlag = "X:"
+
+// ParseLine constructs a puzzle from the line format (see Puzzle.MarshalText). Options may be
+// passed to further configure the puzzle, though the box dimensions are those of the line. An
+// error wrapping ErrLineFormat is returned for malformed lines, otherwise, any error of NewPuzzle
+// is returned.
+func ParseLine(line string, opts ...PuzzleOption) (Puzzle, error) {
+	// Split the optional cages of Killer puzzles, the optional diagonals flag of X-Sudoku, then the
+	// optional geometry or region prefix.
+	sections := strings.Split(line, ";")
+	line = sections[0]
+	diagonals := strings.HasPrefix(line, diagonalsFlag)
+	line = strings.TrimPrefix(line, diagonalsFlag)
+	var boxHeight, boxWidth int
+	var regions string
+	cells := line
+	i := strings.IndexByte(line, ':')
+	switch {
+	case i == -1:
+	case isGeometry(line[:i]):
+		geometry := strings.Split(line[:i], "x")
+		var err error
+		if boxHeight, err = parseDimension(geometry[0]); err != nil {
+			return Puzzle{}, err
+		}
+		if boxWidth, err = parseDimension(geometry[1]); err != nil {
+			return Puzzle{}, err
+		}
+		cells = line[i+1:]
+	case line[:i] == "":
+		return Puzzle{}, fmt.Errorf("%w: empty prefix", ErrLineFormat)
+	default:
+		// The prefix of jigsaw puzzles holds the region of every position.
+		regions, cells = line[:i], line[i+1:]
+	}
+
+	// The number of cells must be the square of a side that fits the digits.
+	side := int(math.Sqrt(float64(len(cells))))
+	if side == 0 || side*side != len(cells) {
+		return Puzzle{}, fmt.Errorf("%w: %d cells do not form a square", ErrLineFormat, len(cells))
+	}
+	if side > maxLineSide {
+		return Puzzle{}, fmt.Errorf("%w: side %d is larger than %d", ErrLineFormat, side, maxLineSide)
+	}
+
+	arr := make([][]PuzzleInt, side)
+	for row := range arr {
+		arr[row] = make([]PuzzleInt, side)
+		for col := range arr[row] {
+			val, ok := lineValue(cells[row*side+col])
+			if !ok {
+				return Puzzle{}, fmt.Errorf("%w: invalid character %q at row %d, column %d", ErrLineFormat,
+					cells[row*side+col], row, col)
+			}
+			arr[row][col] = val
+		}
+	}
+
+	if boxHeight != 0 {
+		opts = append(opts, WithBoxDimensions(PuzzleInt(boxHeight), PuzzleInt(boxWidth)))
+	}
+	if regions != "" {
+		if len(regions) != len(cells) {
+			return Puzzle{}, fmt.Errorf("%w: %d regions for %d cells", ErrLineFormat, len(regions), len(cells))
+		}
+		regionMap := make([][]PuzzleInt, side)
+		for row := range regionMap {
+			regionMap[row] = make([]PuzzleInt, side)
+			for col := range regionMap[row] {
+				id, ok := regionID(regions[row*side+col])
+				if !ok {
+					return Puzzle{}, fmt.Errorf("%w: invalid region %q at row %d, column %d", ErrLineFormat,
+						regions[row*side+col], row, col)
+				}
+				regionMap[row][col] = id
+			}
+		}
+		opts = append(opts, WithRegions(regionMap))
+	}
+	if diagonals {
+		opts = append(opts, WithDiagonals())
+	}
+	if len(sections) > 1 {
+		cages := make([]Cage, len(sections)-1)
+		for i, s := range sections[1:] {
+			var err error
+			if cages[i], err = parseCage(s, side); err != nil {
+				return Puzzle{}, err
+			}
+		}
+		opts = append(opts, WithCages(cages...))
+	}
+	return NewPuzzle(arr, opts...)
+}
+
+// parseCage parses a cage of the line format, the row-major indices of its cells joined by '+',
+// followed by '=' and its sum, in a puzzle of the passed side.
+func parseCage(s string, side int) (Cage, error) {
+	i := strings.IndexByte(s, '=')
+	if i == -1 {
+		return Cage{}, fmt.Errorf("%w: cage %q is not of the form cells=sum", ErrLineFormat, s)
+	}
+	sum, err := parseDecimal(s[i+1:])
+	if err != nil {
+		return Cage{}, fmt.Errorf("%w: invalid sum of cage %q", ErrLineFormat, s)
+	}
+	cage := Cage{Sum: sum}
+	for _, index := range strings.Split(s[:i], "+") {
+		n, err := parseDecimal(index)
+		if err != nil || n >= side*side {
+			return Cage{}, fmt.Errorf("%w: invalid cell %q of cage %q", ErrLineFormat, index, s)
+		}
+		cage.Cells = append(cage.Cells, Cell{PuzzleInt(n / side), PuzzleInt(n % side)})
+	}
+	return cage, nil
+}
+
+// parseDecimal parses a non-negative decimal number, without a sign.
+func parseDecimal(s string) (int, error) {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, strconv.ErrSyntax
+	}
+	return strconv.Atoi(s)
+}
+
+// isGeometry returns whether or not the prefix s is a geometry of the form HxW, with decimal box
+// dimensions. Other prefixes are region maps, where 'x' is a region like any other digit.
+func isGeometry(s string) bool {
+	i := strings.IndexByte(s, 'x')
+	return i > 0 && i < len(s)-1 && strings.TrimLeft(s[:i], "0123456789") == "" &&
+		strings.TrimLeft(s[i+1:], "0123456789") == ""
+}
+
+// parseDimension parses a box dimension of the geometry prefix, a positive decimal number.
+func parseDimension(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 || n > maxLineSide || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, fmt.Errorf("%w: invalid box dimension %q", ErrLineFormat, s)
+	}
+	return n, nil
+}
+
+// lineValue returns the value of a character of the line format, where ok is false for invalid
+// characters.
+func lineValue(c byte) (val PuzzleInt, ok bool) {
+	switch {
+	case c == '.' || c == '0':
+		return 0, true
+	case c >= '1' && c <= '9':
+		return PuzzleInt(c - '0'), true
+	case c >= 'A' && c <= 'Z':
+		return PuzzleInt(c-'A') + 10, true
+	case c >= 'a' && c <= 'z':
+		return PuzzleInt(c-'a') + 10, true
+	}
+	return 0, false
+}
+
+// regionID returns the region of a character of the region prefix, a base-36 digit, where ok is
+// false for invalid characters.
+func regionID(c byte) (id PuzzleInt, ok bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return PuzzleInt(c - '0'), true
+	case c >= 'A' && c <= 'Z':
+		return PuzzleInt(c-'A') + 10, true
+	case c >= 'a' && c <= 'z':
+		return PuzzleInt(c-'a') + 10, true
+	}
+	return 0, false
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for Puzzle, encoding it in the line
+// format: every position in row major order, as a single base-36 digit (1-9, then A-Z from 10),
+// with '.' for vacant positions. Boxes that differ from the default of NewPuzzle are encoded in a
+// geometry prefix of the box height and width, such as "2x3:" for a 6x6 puzzle. For example, a
+// 4x4 puzzle with a single value is encoded as
+//
+//	1...............
+//
+// The region map of jigsaw puzzles (see WithRegions) takes the place of the geometry prefix, as the
+// base-36 region of every position in row major order, such as "0001201122312333:" for a 4x4
+// puzzle.
+//
+// X-Sudoku puzzles (see WithDiagonals) are marked with an "X:" flag ahead of any other prefix, such
+// as "X:2x3:" for a 6x6 puzzle.
+//
+// The cages of Killer puzzles (see WithCages) follow the positions, each as ';' and the row-major
+// indices of its cells joined by '+', then '=' and its sum, such as ";0+1+4=7" for a cage of the
+// first two cells of the first row and the first cell of the second row of a 4x4 puzzle.
+//
+// Puzzles with a side larger than 35 cannot be encoded, and return an error wrapping
+// ErrLineFormat.
+func (p Puzzle) MarshalText() ([]byte, error) {
+	side := len(p.Arr)
+	if side > maxLineSide {
+		return nil, fmt.Errorf("%w: side %d is larger than %d", ErrLineFormat, side, maxLineSide)
+	}
+
+	var sb strings.Builder
+	if p.hasConstraint(Diagonals) {
+		sb.WriteString(diagonalsFlag)
+	}
+	// The geometry is only needed when it differs from the default of NewPuzzle.
+	if p.regions != nil {
+		for _, ids := range p.regions {
+			for _, id := range ids {
+				sb.WriteString(strings.ToUpper(strconv.FormatUint(uint64(id), 36)))
+			}
+		}
+		sb.WriteByte(':')
+	} else if def := PuzzleInt(math.Sqrt(float64(side))); p.boxHeight != def || p.boxWidth != def {
+		fmt.Fprintf(&sb, "%dx%d:", p.boxHeight, p.boxWidth)
+	}
+	for _, row := range p.Arr {
+		for _, val := range row {
+			if val == 0 {
+				sb.WriteByte('.')
+				continue
+			}
+			sb.WriteString(strings.ToUpper(strconv.FormatUint(uint64(val), 36)))
+		}
+	}
+	for _, cage := range p.cages {
+		for i, c := range cage.Cells {
+			if i == 0 {
+				sb.WriteByte(';')
+			} else {
+				sb.WriteByte('+')
+			}
+			sb.WriteString(strconv.Itoa(p.cellIndex(c.Row, c.Col)))
+		}
+		fmt.Fprintf(&sb, "=%d", cage.Sum)
+	}
+	return []byte(sb.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for Puzzle, decoding it from the
+// line format (see MarshalText) with the default configuration of NewPuzzle. The text is parsed
+// strictly, with an error returned for any malformed line (see ParseLine).
+func (p *Puzzle) UnmarshalText(text []byte) error {
+	puzzle, err := ParseLine(string(text))
+	if err != nil {
+		return err
+	}
+	*p = puzzle
+	return nil
+}
@@ -0,0 +1,223 @@
+package sudoku
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineRoundTrip(t *testing.T) {
+	testCases := []struct {
+		line string
+		arr  [][]PuzzleInt
+		opts []PuzzleOption
+	}{
+		{
+			line: "1..2.3..........",
+			arr: [][]PuzzleInt{
+				{1, 0, 0, 2},
+				{0, 3, 0, 0},
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+			},
+		},
+		{
+			// Rectangular boxes need the geometry.
+			line: "2x3:51..2...4.....2.......65..5.......13",
+			arr: [][]PuzzleInt{
+				{5, 1, 0, 0, 2, 0},
+				{0, 0, 4, 0, 0, 0},
+				{0, 0, 2, 0, 0, 0},
+				{0, 0, 0, 0, 6, 5},
+				{0, 0, 5, 0, 0, 0},
+				{0, 0, 0, 0, 1, 3},
+			},
+			opts: []PuzzleOption{WithBoxDimensions(2, 3)},
+		},
+		{
+			// Boxes spanning a whole row differ from the default.
+			line: "1x4:1...............",
+			arr: [][]PuzzleInt{
+				{1, 0, 0, 0},
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+			},
+			opts: []PuzzleOption{WithBoxDimensions(1, 4)},
+		},
+		{
+			// The regions of jigsaw puzzles take the place of the geometry.
+			line: "0001201122312333:1...............",
+			arr: [][]PuzzleInt{
+				{1, 0, 0, 0},
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+			},
+			opts: []PuzzleOption{WithRegions(jigsaw4x4)},
+		},
+		{
+			// The diagonals flag of X-Sudoku comes ahead of any other prefix.
+			line: "X:1x4:1...............",
+			arr: [][]PuzzleInt{
+				{1, 0, 0, 0},
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+			},
+			opts: []PuzzleOption{WithBoxDimensions(1, 4), WithDiagonals()},
+		},
+		{
+			// Killer cages follow the positions.
+			line: "1...............;0+1+4=7;15=4",
+			arr: [][]PuzzleInt{
+				{1, 0, 0, 0},
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+			},
+			opts: []PuzzleOption{WithCages(
+				Cage{Sum: 7, Cells: []Cell{{0, 0}, {0, 1}, {1, 0}}},
+				Cage{Sum: 4, Cells: []Cell{{3, 3}}},
+			)},
+		},
+	}
+	for _, tc := range testCases {
+		puzzle, err := NewPuzzle(tc.arr, tc.opts...)
+		require.NoError(t, err)
+		text, err := puzzle.MarshalText()
+		require.NoError(t, err)
+		require.Equal(t, tc.line, string(text))
+
+		parsed, err := ParseLine(tc.line)
+		require.NoError(t, err, tc.line)
+		require.Equal(t, tc.arr, parsed.Arr, tc.line)
+		require.Equal(t, puzzle.boxHeight, parsed.boxHeight, tc.line)
+		require.Equal(t, puzzle.boxWidth, parsed.boxWidth, tc.line)
+		require.Equal(t, puzzle.Regions(), parsed.Regions(), tc.line)
+		require.Equal(t, puzzle.Cages(), parsed.Cages(), tc.line)
+		require.Equal(t, puzzle.hasConstraint(Diagonals), parsed.hasConstraint(Diagonals), tc.line)
+	}
+}
+
+func TestLineRegionDigits(t *testing.T) {
+	// Regions from 10 are letters too, so a region map may hold an 'x' without being a geometry.
+	const side = 34
+	regions := make([][]PuzzleInt, side)
+	var prefix strings.Builder
+	for row := range regions {
+		regions[row] = make([]PuzzleInt, side)
+		for col := range regions[row] {
+			regions[row][col] = PuzzleInt(row)
+			prefix.WriteString(strconv.FormatInt(int64(row), 36))
+		}
+	}
+	require.Contains(t, prefix.String(), "x")
+
+	puzzle, err := ParseLine(prefix.String() + ":" + strings.Repeat(".", side*side))
+	require.NoError(t, err)
+	require.Equal(t, regions, puzzle.Regions())
+	text, err := puzzle.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, strings.ToUpper(prefix.String()), string(text[:side*side]))
+}
+
+func TestLineDiagonals(t *testing.T) {
+	puzzle, err := NewPuzzle(emptyArr(4), WithDiagonals())
+	require.NoError(t, err)
+	text, err := puzzle.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "X:................", string(text))
+
+	// The variant survives the round trip, even when passed again.
+	parsed, err := ParseLine(string(text), WithDiagonals())
+	require.NoError(t, err)
+	require.Equal(t, 48, puzzle.CountSolutions(0))
+	require.Equal(t, 48, parsed.CountSolutions(0))
+	require.Len(t, parsed.units, len(puzzle.units))
+}
+
+func TestLineDigits(t *testing.T) {
+	// Values from 10 are letters, in either case.
+	arr := emptyArr(16)
+	arr[0] = []PuzzleInt{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	puzzle, err := NewPuzzle(arr)
+	require.NoError(t, err)
+	text, err := puzzle.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "123456789ABCDEFG", string(text[:16]))
+
+	lower, err := ParseLine("123456789abcdefg" + string(text[16:]))
+	require.NoError(t, err)
+	require.Equal(t, arr, lower.Arr)
+
+	// Blanks are either '.' or '0'.
+	blanks, err := ParseLine("1.0.............")
+	require.NoError(t, err)
+	require.Equal(t, []PuzzleInt{1, 0, 0, 0}, blanks.Arr[0])
+
+	// Puzzles larger than the digits cannot be encoded.
+	large, err := NewPuzzle(emptyArr(36))
+	require.NoError(t, err)
+	_, err = large.MarshalText()
+	require.ErrorIs(t, err, ErrLineFormat)
+}
+
+func TestParseLineErrors(t *testing.T) {
+	testCases := []struct {
+		line string
+		err  error
+	}{
+		{"", ErrLineFormat},
+		{"1.2", ErrLineFormat},
+		{"1..2.3.........#", ErrLineFormat},
+		{"1..2.3.........x", ErrValueRange},
+		{"1..2.3......... ", ErrLineFormat},
+		{"1..2.3..........\n", ErrLineFormat},
+		{"5...............", ErrValueRange},
+		{"3x3:1...............", ErrBoxDimensions},
+		{"4x4:1...............", ErrBoxDimensions},
+		{"2:1...............", ErrLineFormat},
+		{"2x:1...............", ErrLineFormat},
+		{"+2x2:1...............", ErrLineFormat},
+		{"2x3:" + "......" + "......" + "......" + "......" + "......", ErrLineFormat},
+		{"2x3:" + "......" + "......" + "......" + "......" + "......" + "......" + "......", ErrLineFormat},
+		{":1...............", ErrLineFormat},
+		{"X:", ErrLineFormat},
+		{"2x2x2:1...............", ErrLineFormat},
+		{"0x2:1...............", ErrLineFormat},
+		{"X:X:1...............", ErrLineFormat},
+		{"000120112231233:1...............", ErrLineFormat},
+		{"000120112231233#:1...............", ErrLineFormat},
+		{"0001201122312334:1...............", ErrRegions},
+		{"................;", ErrLineFormat},
+		{"................;0+1", ErrLineFormat},
+		{"................;0+1=", ErrLineFormat},
+		{"................;0+1=-3", ErrLineFormat},
+		{"................;0++1=3", ErrLineFormat},
+		{"................;0+16=3", ErrLineFormat},
+		{"................;0+1=3;1+2=3", ErrCage},
+		{"................;0+1=9", ErrCage},
+	}
+	for _, tc := range testCases {
+		_, err := ParseLine(tc.line)
+		require.ErrorIs(t, err, tc.err, tc.line)
+	}
+}
+
+func TestPuzzleJSON(t *testing.T) {
+	// Puzzles encode as their line in JSON.
+	var v struct {
+		Puzzle Puzzle `json:"puzzle"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"puzzle": "2x3:51..2...4.....2.......65..5.......13"}`), &v))
+	require.True(t, v.Puzzle.Solve())
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t, `{"puzzle":"2x3:513426264351652134341265135642426513"}`, string(b))
+
+	require.Error(t, json.Unmarshal([]byte(`{"puzzle": "1.2"}`), &v))
+}
@@ -0,0 +1,754 @@
+package sudoku
+
+import (
+	"context"
+	"fmt"
+)
+
+// Technique represents a human solving technique.
+type Technique int
+
+// Techniques of the logical solver, in the order they are attempted.
+const (
+	NakedSingle Technique = iota
+	HiddenSingle
+	NakedPair
+	HiddenPair
+	NakedTriple
+	HiddenTriple
+	PointingPair
+	BoxLineReduction
+	XWing
+	Swordfish
+	XYWing
+	// Guess is not a technique, but a fallback to the backtracking solver when stuck.
+	Guess
+)
+
+// techniqueNames maps a Technique to its name.
+var techniqueNames = map[Technique]string{
+	NakedSingle:      "naked_single",
+	HiddenSingle:     "hidden_single",
+	NakedPair:        "naked_pair",
+	HiddenPair:       "hidden_pair",
+	NakedTriple:      "naked_triple",
+	HiddenTriple:     "hidden_triple",
+	PointingPair:     "pointing_pair",
+	BoxLineReduction: "box_line_reduction",
+	XWing:            "x_wing",
+	Swordfish:        "swordfish",
+	XYWing:           "xy_wing",
+	Guess:            "guess",
+}
+
+// String implements the Stringer interface for Technique.
+func (t Technique) String() string {
+	if name, ok := techniqueNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("Technique(%d)", int(t))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for Technique, encoding it by name.
+func (t Technique) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for Technique, decoding it by name.
+func (t *Technique) UnmarshalText(text []byte) error {
+	for tech, name := range techniqueNames {
+		if name == string(text) {
+			*t = tech
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown technique %q", text)
+}
+
+// Candidate represents a value of a cell, either placed or eliminated by a step.
+type Candidate struct {
+	Cell
+	Value PuzzleInt `json:"value"`
+}
+
+// Step represents a single deduction of the logical solver. A step either places a value or
+// eliminates candidates. Cells lists the cells that justify the deduction: the occupied cells
+// that rule out the alternatives of a single, or the cells forming the pattern of other
+// techniques. The eliminations implied by placing a value are not listed.
+type Step struct {
+	Technique    Technique   `json:"technique"`
+	Placement    *Candidate  `json:"placement,omitempty"`
+	Eliminations []Candidate `json:"eliminations,omitempty"`
+	Cells        []Cell      `json:"cells,omitempty"`
+}
+
+// LogicalResult represents the outcome of a logical solve.
+type LogicalResult struct {
+	// Steps is the ordered log of deductions.
+	Steps []Step
+	// Arr is the matrix after applying all steps, which is complete when Solved is true.
+	Arr [][]PuzzleInt
+	// Solved is true when all positions were filled.
+	Solved bool
+	// Guesses is the number of times the solver was stuck and fell back to guessing.
+	Guesses int
+}
+
+// SolveLogical solves a copy of the puzzle the way a person would, by applying techniques in
+// order of difficulty and restarting from the simplest technique after every step. When no
+// technique applies, the solver is stuck and falls back to guessing: the cell with the fewest
+// candidates is filled from a backtracking solution and logged as a Guess step. The puzzle is
+// left untouched.
+func (p Puzzle) SolveLogical() LogicalResult {
+	return newLogicalSolver(p).run()
+}
+
+// run applies techniques until the puzzle is solved or stuck without a solution to guess from
+// (see SolveLogical).
+func (s *logicalSolver) run() LogicalResult {
+	res := LogicalResult{}
+	for s.valid() && !s.solved() {
+		step, ok := s.next()
+		if !ok {
+			// Stuck, fall back to guessing.
+			if step, ok = s.guess(); !ok {
+				break
+			}
+			res.Guesses++
+		}
+		res.Steps = append(res.Steps, step)
+	}
+	res.Arr = s.arr()
+	res.Solved = s.solved()
+	return res
+}
+
+// logicalSolver represents the state of a logical solve, with the cells of the puzzle flattened
+// in row-major order.
+type logicalSolver struct {
+	p    Puzzle
+	side int
+
+	grid  []PuzzleInt // The value of each cell, or 0 if vacant
+	cands []bitSet    // The candidates of each vacant cell
+
+	units     []unit  // All units of the puzzle
+	unitCells [][]int // The cell indices of each unit
+	cellUnits [][]int // The unit indices of each cell
+	inUnit    [][]bool
+	peers     [][]int // The cell indices sharing a unit with each cell
+
+	solution []PuzzleInt // Backtracking solution, calculated on the first guess
+	err      error       // Reason the backtracking solution could not be calculated
+}
+
+// newLogicalSolver constructs a logical solver from the values of p, with the candidates of
+// every vacant cell calculated from the constraints of the puzzle.
+func newLogicalSolver(p Puzzle) *logicalSolver {
+	side := len(p.Arr)
+	s := &logicalSolver{
+		p:         p,
+		side:      side,
+		grid:      make([]PuzzleInt, side*side),
+		cands:     make([]bitSet, side*side),
+		units:     p.units,
+		cellUnits: make([][]int, side*side),
+		peers:     make([][]int, side*side),
+	}
+
+	// Flatten the units.
+	s.unitCells = make([][]int, len(s.units))
+	s.inUnit = make([][]bool, len(s.units))
+	for u, un := range s.units {
+		s.inUnit[u] = make([]bool, side*side)
+		for _, c := range un.Cells {
+			i := s.index(c)
+			s.unitCells[u] = append(s.unitCells[u], i)
+			s.cellUnits[i] = append(s.cellUnits[i], u)
+			s.inUnit[u][i] = true
+		}
+	}
+	// Calculate peers, without duplicates.
+	for i := range s.peers {
+		seen := make(map[int]bool)
+		for _, u := range s.cellUnits[i] {
+			for _, j := range s.unitCells[u] {
+				if j != i && !seen[j] {
+					seen[j] = true
+					s.peers[i] = append(s.peers[i], j)
+				}
+			}
+		}
+	}
+
+	// Copy the values and candidates, using a copy of the puzzle for the constraint checks.
+	c := p.clone()
+	for row := PuzzleInt(0); int(row) < side; row++ {
+		for col := PuzzleInt(0); int(col) < side; col++ {
+			i := int(row)*side + int(col)
+			s.grid[i] = c.Arr[row][col]
+			s.cands[i] = c.candidates(row, col)
+		}
+	}
+	return s
+}
+
+// index returns the flattened index of the cell.
+func (s *logicalSolver) index(c Cell) int {
+	return int(c.Row)*s.side + int(c.Col)
+}
+
+// cell returns the cell of the flattened index.
+func (s *logicalSolver) cell(i int) Cell {
+	return Cell{PuzzleInt(i / s.side), PuzzleInt(i % s.side)}
+}
+
+// arr returns the values of the solver as a matrix.
+func (s *logicalSolver) arr() [][]PuzzleInt {
+	arr := make([][]PuzzleInt, s.side)
+	for row := range arr {
+		arr[row] = append([]PuzzleInt(nil), s.grid[row*s.side:(row+1)*s.side]...)
+	}
+	return arr
+}
+
+// solved returns whether or not all cells are occupied.
+func (s *logicalSolver) solved() bool {
+	for _, v := range s.grid {
+		if v == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// valid returns whether or not the values have no conflicts and every vacant cell has at least
+// one candidate.
+func (s *logicalSolver) valid() bool {
+	for i, v := range s.grid {
+		if v == 0 && s.cands[i].Count() == 0 {
+			return false
+		}
+	}
+	return len(s.p.withArr(s.arr()).Validate()) == 0
+}
+
+// has returns whether or not val is a candidate of the vacant cell i.
+func (s *logicalSolver) has(i int, val PuzzleInt) bool {
+	return s.grid[i] == 0 && s.cands[i].Get(int(val)) == 1
+}
+
+// values returns the candidates of cell i, in ascending order.
+func (s *logicalSolver) values(i int) []PuzzleInt {
+	var vals []PuzzleInt
+	for val := PuzzleInt(1); int(val) <= s.side; val++ {
+		if s.has(i, val) {
+			vals = append(vals, val)
+		}
+	}
+	return vals
+}
+
+// place sets val in cell i, removing it from the candidates of its peers.
+func (s *logicalSolver) place(i int, val PuzzleInt) {
+	s.grid[i] = val
+	s.cands[i].Reset()
+	for _, j := range s.peers[i] {
+		s.cands[j].Set(int(val), 0)
+	}
+}
+
+// eliminate removes val from the candidates of cell i, appending the elimination to elims if
+// val was a candidate.
+func (s *logicalSolver) eliminate(elims []Candidate, i int, val PuzzleInt) []Candidate {
+	if !s.has(i, val) {
+		return elims
+	}
+	s.cands[i].Set(int(val), 0)
+	return append(elims, Candidate{s.cell(i), val})
+}
+
+// isPeer returns whether or not cells i and j share a unit.
+func (s *logicalSolver) isPeer(i, j int) bool {
+	for _, u := range s.cellUnits[i] {
+		if s.inUnit[u][j] {
+			return true
+		}
+	}
+	return false
+}
+
+// blocker returns the first peer of cell i that is occupied by val.
+func (s *logicalSolver) blocker(i int, val PuzzleInt) (int, bool) {
+	for _, j := range s.peers[i] {
+		if s.grid[j] == val {
+			return j, true
+		}
+	}
+	return 0, false
+}
+
+// cellsOf returns the cells of the flattened indices.
+func (s *logicalSolver) cellsOf(indices []int) []Cell {
+	cells := make([]Cell, len(indices))
+	for n, i := range indices {
+		cells[n] = s.cell(i)
+	}
+	return cells
+}
+
+// techniques returns the techniques of the solver, in the order they are attempted.
+func (s *logicalSolver) techniques() []func() (Step, bool) {
+	return []func() (Step, bool){
+		s.nakedSingle,
+		s.hiddenSingle,
+		func() (Step, bool) { return s.nakedSubset(NakedPair, 2) },
+		func() (Step, bool) { return s.hiddenSubset(HiddenPair, 2) },
+		func() (Step, bool) { return s.nakedSubset(NakedTriple, 3) },
+		func() (Step, bool) { return s.hiddenSubset(HiddenTriple, 3) },
+		s.pointing,
+		s.boxLineReduction,
+		func() (Step, bool) { return s.fish(XWing, 2) },
+		func() (Step, bool) { return s.fish(Swordfish, 3) },
+		s.xyWing,
+	}
+}
+
+// next applies the simplest technique that makes progress, returning its step. If no technique
+// applies, ok is false.
+func (s *logicalSolver) next() (step Step, ok bool) {
+	step, ok, _ = s.nextContext(context.Background())
+	return step, ok
+}
+
+// nextContext applies the first technique that makes progress like next, returning the error of
+// ctx if it is done before a technique is attempted.
+func (s *logicalSolver) nextContext(ctx context.Context) (step Step, ok bool, err error) {
+	for _, technique := range s.techniques() {
+		if err := ctx.Err(); err != nil {
+			return Step{}, false, err
+		}
+		if step, ok = technique(); ok {
+			return step, true, nil
+		}
+	}
+	return Step{}, false, nil
+}
+
+// guess fills the vacant cell with the fewest candidates with its value from a backtracking
+// solution. If the puzzle has no solution, ok is false.
+func (s *logicalSolver) guess() (step Step, ok bool) {
+	if s.solution == nil {
+		puzzle := s.p.withArr(s.arr())
+		if _, err := puzzle.SolveContext(context.Background()); err != nil {
+			s.err = err
+			return Step{}, false
+		}
+		for _, row := range puzzle.Arr {
+			s.solution = append(s.solution, row...)
+		}
+	}
+
+	// Find the vacant cell with the fewest candidates.
+	best := -1
+	for i, v := range s.grid {
+		if v == 0 && (best == -1 || s.cands[i].Count() < s.cands[best].Count()) {
+			best = i
+		}
+	}
+	val := s.solution[best]
+	s.place(best, val)
+	return Step{Technique: Guess, Placement: &Candidate{s.cell(best), val}}, true
+}
+
+// nakedSingle places the only candidate of a cell. The peers occupied by the other values
+// justify the placement.
+func (s *logicalSolver) nakedSingle() (Step, bool) {
+	for i := range s.grid {
+		if s.grid[i] != 0 || s.cands[i].Count() != 1 {
+			continue
+		}
+		val := s.values(i)[0]
+
+		// Find a peer occupied by each of the other values.
+		var blockers []int
+		for other := PuzzleInt(1); int(other) <= s.side; other++ {
+			if j, ok := s.blocker(i, other); ok && other != val {
+				blockers = append(blockers, j)
+			}
+		}
+		s.place(i, val)
+		return Step{Technique: NakedSingle, Placement: &Candidate{s.cell(i), val}, Cells: s.cellsOf(blockers)}, true
+	}
+	return Step{}, false
+}
+
+// hiddenSingle places a value that can only go in one cell of a unit. The peers occupied by the
+// value, ruling out the other vacant cells of the unit, justify the placement.
+func (s *logicalSolver) hiddenSingle() (Step, bool) {
+	for u, cells := range s.unitCells {
+		// Only units with every value can be used.
+		if len(cells) != s.side {
+			continue
+		}
+		for val := PuzzleInt(1); int(val) <= s.side; val++ {
+			// Find the cells of the unit with the candidate.
+			var found []int
+			for _, i := range cells {
+				if s.has(i, val) {
+					found = append(found, i)
+				}
+			}
+			if len(found) != 1 {
+				continue
+			}
+			i := found[0]
+
+			// Find the peers occupied by val for the other vacant cells of the unit.
+			var blockers []int
+			seen := make(map[int]bool)
+			for _, j := range s.unitCells[u] {
+				if s.grid[j] != 0 || j == i {
+					continue
+				}
+				if k, ok := s.blocker(j, val); ok && !seen[k] {
+					seen[k] = true
+					blockers = append(blockers, k)
+				}
+			}
+			s.place(i, val)
+			return Step{Technique: HiddenSingle, Placement: &Candidate{s.cell(i), val}, Cells: s.cellsOf(blockers)}, true
+		}
+	}
+	return Step{}, false
+}
+
+// nakedSubset finds n cells of a unit whose candidates are limited to the same n values, which
+// are then eliminated from the other cells of the unit.
+func (s *logicalSolver) nakedSubset(tech Technique, n int) (Step, bool) {
+	for _, cells := range s.unitCells {
+		// Vacant cells with at most n candidates can form the subset.
+		var pool []int
+		for _, i := range cells {
+			if count := s.cands[i].Count(); s.grid[i] == 0 && count >= 2 && count <= n {
+				pool = append(pool, i)
+			}
+		}
+
+		var step Step
+		found := combinations(len(pool), n, func(combo []int) bool {
+			var union bitSet
+			subset := make([]int, n)
+			for k, c := range combo {
+				subset[k] = pool[c]
+				union.Union(&s.cands[pool[c]])
+			}
+			if union.Count() != n {
+				return false
+			}
+
+			// Eliminate the values from the other cells of the unit.
+			var elims []Candidate
+			for _, i := range cells {
+				if containsInt(subset, i) {
+					continue
+				}
+				for val := PuzzleInt(1); int(val) <= s.side; val++ {
+					if union.Get(int(val)) == 1 {
+						elims = s.eliminate(elims, i, val)
+					}
+				}
+			}
+			step = Step{Technique: tech, Eliminations: elims, Cells: s.cellsOf(subset)}
+			return len(elims) != 0
+		})
+		if found {
+			return step, true
+		}
+	}
+	return Step{}, false
+}
+
+// hiddenSubset finds n values of a unit whose candidates are limited to the same n cells, in
+// which the other candidates are then eliminated.
+func (s *logicalSolver) hiddenSubset(tech Technique, n int) (Step, bool) {
+	for _, cells := range s.unitCells {
+		// Only units with every value can be used.
+		if len(cells) != s.side {
+			continue
+		}
+		// Values with at most n candidate cells can form the subset.
+		var pool []PuzzleInt
+		for val := PuzzleInt(1); int(val) <= s.side; val++ {
+			var count int
+			for _, i := range cells {
+				if s.has(i, val) {
+					count++
+				}
+			}
+			if count >= 2 && count <= n {
+				pool = append(pool, val)
+			}
+		}
+
+		var step Step
+		found := combinations(len(pool), n, func(combo []int) bool {
+			vals := make([]PuzzleInt, n)
+			for k, c := range combo {
+				vals[k] = pool[c]
+			}
+			// Find the cells containing any of the values.
+			var subset []int
+			for _, i := range cells {
+				for _, val := range vals {
+					if s.has(i, val) {
+						subset = append(subset, i)
+						break
+					}
+				}
+			}
+			if len(subset) != n {
+				return false
+			}
+
+			// Eliminate the other values from the cells of the subset.
+			var elims []Candidate
+			for _, i := range subset {
+				for _, val := range s.values(i) {
+					if !containsVal(vals, val) {
+						elims = s.eliminate(elims, i, val)
+					}
+				}
+			}
+			step = Step{Technique: tech, Eliminations: elims, Cells: s.cellsOf(subset)}
+			return len(elims) != 0
+		})
+		if found {
+			return step, true
+		}
+	}
+	return Step{}, false
+}
+
+// pointing finds a value whose candidates in a box are limited to one row or column, which is
+// then eliminated from the rest of that row or column.
+func (s *logicalSolver) pointing() (Step, bool) {
+	return s.intersection(PointingPair, UnitBox, UnitRow, UnitColumn)
+}
+
+// boxLineReduction finds a value whose candidates in a row or column are limited to one box,
+// which is then eliminated from the rest of that box.
+func (s *logicalSolver) boxLineReduction() (Step, bool) {
+	return s.intersection(BoxLineReduction, UnitRow, UnitBox, UnitColumn, UnitBox)
+}
+
+// intersection eliminates a value from the cells of a target unit outside a source unit, when
+// all candidates of the value in the source unit are also in the target unit. types lists pairs
+// of source and target unit types.
+func (s *logicalSolver) intersection(tech Technique, types ...UnitType) (Step, bool) {
+	for t := 0; t+1 < len(types); t += 2 {
+		from, to := types[t], types[t+1]
+		for a, cells := range s.unitCells {
+			if s.units[a].Type != from {
+				continue
+			}
+			for val := PuzzleInt(1); int(val) <= s.side; val++ {
+				var found []int
+				for _, i := range cells {
+					if s.has(i, val) {
+						found = append(found, i)
+					}
+				}
+				if len(found) < 2 {
+					continue
+				}
+
+				// Find the target units containing all of the candidates.
+				for _, b := range s.cellUnits[found[0]] {
+					if s.units[b].Type != to || !s.containsAll(b, found) {
+						continue
+					}
+					var elims []Candidate
+					for _, i := range s.unitCells[b] {
+						if !s.inUnit[a][i] {
+							elims = s.eliminate(elims, i, val)
+						}
+					}
+					if len(elims) != 0 {
+						return Step{Technique: tech, Eliminations: elims, Cells: s.cellsOf(found)}, true
+					}
+				}
+			}
+		}
+	}
+	return Step{}, false
+}
+
+// containsAll returns whether or not unit u contains all of the cells.
+func (s *logicalSolver) containsAll(u int, cells []int) bool {
+	for _, i := range cells {
+		if !s.inUnit[u][i] {
+			return false
+		}
+	}
+	return true
+}
+
+// fish finds n rows (columns) whose candidates of a value are limited to the same n columns
+// (rows). The value is then eliminated from the other cells of those columns (rows). An X-Wing
+// is a fish of 2 lines, and a Swordfish of 3 lines.
+func (s *logicalSolver) fish(tech Technique, n int) (Step, bool) {
+	for _, byRow := range []bool{true, false} {
+		// cellAt returns the cell at the base line and cover line.
+		cellAt := func(base, cover int) int {
+			if byRow {
+				return base*s.side + cover
+			}
+			return cover*s.side + base
+		}
+
+		for val := PuzzleInt(1); int(val) <= s.side; val++ {
+			// Base lines with 2 to n candidates, and the cover lines of their candidates.
+			var bases []int
+			var covers [][]int
+			for base := 0; base < s.side; base++ {
+				var lines []int
+				for cover := 0; cover < s.side; cover++ {
+					if s.has(cellAt(base, cover), val) {
+						lines = append(lines, cover)
+					}
+				}
+				if len(lines) >= 2 && len(lines) <= n {
+					bases = append(bases, base)
+					covers = append(covers, lines)
+				}
+			}
+
+			var step Step
+			found := combinations(len(bases), n, func(combo []int) bool {
+				// The candidates of the base lines must cover exactly n lines.
+				var union []int
+				var pattern []int
+				baseLines := make([]int, n)
+				for k, c := range combo {
+					baseLines[k] = bases[c]
+					for _, cover := range covers[c] {
+						pattern = append(pattern, cellAt(bases[c], cover))
+						if !containsInt(union, cover) {
+							union = append(union, cover)
+						}
+					}
+				}
+				if len(union) != n {
+					return false
+				}
+
+				// Eliminate the value from the cover lines outside the base lines.
+				var elims []Candidate
+				for _, cover := range union {
+					for base := 0; base < s.side; base++ {
+						if !containsInt(baseLines, base) {
+							elims = s.eliminate(elims, cellAt(base, cover), val)
+						}
+					}
+				}
+				step = Step{Technique: tech, Eliminations: elims, Cells: s.cellsOf(pattern)}
+				return len(elims) != 0
+			})
+			if found {
+				return step, true
+			}
+		}
+	}
+	return Step{}, false
+}
+
+// xyWing finds a pivot cell with candidates {x, y} that sees two pincer cells with candidates
+// {x, z} and {y, z}. Whichever value the pivot takes, one of the pincers is z, so z is
+// eliminated from the cells that see both pincers.
+func (s *logicalSolver) xyWing() (Step, bool) {
+	for pivot := range s.grid {
+		if s.grid[pivot] != 0 || s.cands[pivot].Count() != 2 {
+			continue
+		}
+		pv := s.values(pivot)
+		x, y := pv[0], pv[1]
+
+		for _, a := range s.peers[pivot] {
+			if s.grid[a] != 0 || s.cands[a].Count() != 2 || !s.has(a, x) || s.has(a, y) {
+				continue
+			}
+			// The first pincer is {x, z}.
+			z := s.values(a)[0]
+			if z == x {
+				z = s.values(a)[1]
+			}
+
+			for _, b := range s.peers[pivot] {
+				if b == a || s.grid[b] != 0 || s.cands[b].Count() != 2 || !s.has(b, y) || !s.has(b, z) {
+					continue
+				}
+				// Eliminate z from the cells that see both pincers.
+				var elims []Candidate
+				for _, i := range s.peers[a] {
+					if i != b && i != pivot && s.isPeer(i, b) {
+						elims = s.eliminate(elims, i, z)
+					}
+				}
+				if len(elims) != 0 {
+					return Step{Technique: XYWing, Eliminations: elims, Cells: s.cellsOf([]int{pivot, a, b})}, true
+				}
+			}
+		}
+	}
+	return Step{}, false
+}
+
+// combinations calls fn with every k sized combination of the indices [0, n), in lexicographic
+// order, until fn returns true. combinations returns whether or not fn returned true.
+func combinations(n, k int, fn func(combo []int) bool) bool {
+	if k > n || k <= 0 {
+		return false
+	}
+	combo := make([]int, k)
+	for i := range combo {
+		combo[i] = i
+	}
+	for {
+		if fn(combo) {
+			return true
+		}
+		// Find the rightmost index that can be incremented.
+		i := k - 1
+		for i >= 0 && combo[i] == n-k+i {
+			i--
+		}
+		if i < 0 {
+			return false
+		}
+		combo[i]++
+		for j := i + 1; j < k; j++ {
+			combo[j] = combo[j-1] + 1
+		}
+	}
+}
+
+// containsInt returns whether or not s contains v.
+func containsInt(s []int, v int) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+// containsVal returns whether or not s contains v.
+func containsVal(s []PuzzleInt, v PuzzleInt) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,214 @@
+package sudoku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// lineArr parses a puzzle from a row-major line of digits, where any other character is vacant.
+func lineArr(line string, side int) [][]PuzzleInt {
+	arr := make([][]PuzzleInt, side)
+	for i := range arr {
+		arr[i] = make([]PuzzleInt, side)
+		for j := range arr[i] {
+			if c := line[i*side+j]; c >= '1' && c <= '9' {
+				arr[i][j] = PuzzleInt(c - '0')
+			}
+		}
+	}
+	return arr
+}
+
+// techniqueCounts returns the number of steps of each technique.
+func techniqueCounts(steps []Step) map[Technique]int {
+	counts := make(map[Technique]int)
+	for _, step := range steps {
+		counts[step.Technique]++
+	}
+	return counts
+}
+
+func TestSolveLogical(t *testing.T) {
+	testCases := []struct {
+		line       string
+		techniques []Technique // Techniques that must be used
+		guesses    bool
+	}{
+		{
+			line:       "003020600900305001001806400008102900700000008006708200002609500800203009005010300",
+			techniques: []Technique{NakedSingle},
+		},
+		{
+			line:       "100000569492056108056109240009640801064010000218035604040500016905061402621000005",
+			techniques: []Technique{NakedSingle, HiddenSingle, HiddenPair, PointingPair, BoxLineReduction, XWing},
+		},
+		{
+			line:       "800400910003000000000003004000001040058000700070006800000002000000000160910060500",
+			techniques: []Technique{NakedSingle, HiddenSingle, NakedPair, HiddenPair, Guess},
+			guesses:    true,
+		},
+	}
+	for _, tc := range testCases {
+		arr := lineArr(tc.line, 9)
+		puzzle, err := NewPuzzle(arr)
+		require.NoError(t, err)
+
+		res := puzzle.SolveLogical()
+		require.True(t, res.Solved)
+		require.Equal(t, tc.guesses, res.Guesses != 0)
+		counts := techniqueCounts(res.Steps)
+		for _, tech := range tc.techniques {
+			require.NotZero(t, counts[tech], tech.String())
+		}
+		require.Equal(t, res.Guesses, counts[Guess])
+
+		// The puzzle is untouched, and the logical solution matches the backtracking solution.
+		require.Equal(t, lineArr(tc.line, 9), puzzle.Arr)
+		require.True(t, puzzle.Solve())
+		require.Equal(t, puzzle.Arr, res.Arr)
+	}
+}
+
+func TestSolveLogicalInvalid(t *testing.T) {
+	puzzle, err := NewPuzzle([][]PuzzleInt{
+		{1, 2, 0, 0},
+		{0, 0, 3, 4},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	})
+	require.NoError(t, err)
+
+	res := puzzle.SolveLogical()
+	require.False(t, res.Solved)
+}
+
+func TestNakedSingleCells(t *testing.T) {
+	puzzle, err := NewPuzzle([][]PuzzleInt{
+		{1, 2, 3, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	})
+	require.NoError(t, err)
+
+	step, ok := newLogicalSolver(puzzle).next()
+	require.True(t, ok)
+	require.Equal(t, Step{
+		Technique: NakedSingle,
+		Placement: &Candidate{Cell{0, 3}, 4},
+		Cells:     []Cell{{0, 0}, {0, 1}, {0, 2}},
+	}, step)
+}
+
+// newEmptySolver returns a logical solver of an empty 9x9 puzzle, where every value is a
+// candidate of every cell.
+func newEmptySolver(t *testing.T) *logicalSolver {
+	puzzle, err := NewPuzzle(emptyArr(9))
+	require.NoError(t, err)
+	return newLogicalSolver(puzzle)
+}
+
+// setCands sets the candidates of the cell at row and col to vals.
+func setCands(s *logicalSolver, row, col int, vals ...PuzzleInt) {
+	i := row*s.side + col
+	s.cands[i].Reset()
+	for _, val := range vals {
+		s.cands[i].Set(int(val), 1)
+	}
+}
+
+// keepInRow eliminates val from the cells of row, except the cells of cols.
+func keepInRow(s *logicalSolver, row int, val PuzzleInt, cols ...int) {
+	for col := 0; col < s.side; col++ {
+		if !containsInt(cols, col) {
+			s.cands[row*s.side+col].Set(int(val), 0)
+		}
+	}
+}
+
+func TestNakedPair(t *testing.T) {
+	s := newEmptySolver(t)
+	setCands(s, 0, 0, 1, 2)
+	setCands(s, 0, 1, 1, 2)
+
+	step, ok := s.nakedSubset(NakedPair, 2)
+	require.True(t, ok)
+	require.Equal(t, []Cell{{0, 0}, {0, 1}}, step.Cells)
+	// Both values are eliminated from the other 7 cells of the row.
+	require.Len(t, step.Eliminations, 14)
+	require.Equal(t, Candidate{Cell{0, 2}, 1}, step.Eliminations[0])
+	require.False(t, s.has(8, 2))
+}
+
+func TestPointingPair(t *testing.T) {
+	s := newEmptySolver(t)
+	// 5 is only a candidate of the first row in the first box.
+	for _, i := range []int{9, 10, 11, 18, 19, 20} {
+		s.cands[i].Set(5, 0)
+	}
+
+	step, ok := s.pointing()
+	require.True(t, ok)
+	require.Equal(t, []Cell{{0, 0}, {0, 1}, {0, 2}}, step.Cells)
+	require.Len(t, step.Eliminations, 6)
+	for _, elim := range step.Eliminations {
+		require.Equal(t, PuzzleInt(0), elim.Row)
+		require.Equal(t, PuzzleInt(5), elim.Value)
+	}
+}
+
+func TestXWing(t *testing.T) {
+	s := newEmptySolver(t)
+	keepInRow(s, 0, 1, 1, 7)
+	keepInRow(s, 4, 1, 1, 7)
+
+	step, ok := s.fish(XWing, 2)
+	require.True(t, ok)
+	require.Equal(t, []Cell{{0, 1}, {0, 7}, {4, 1}, {4, 7}}, step.Cells)
+	// 1 is eliminated from both columns in the other 7 rows.
+	require.Len(t, step.Eliminations, 14)
+	require.False(t, s.has(1*9+1, 1))
+	require.True(t, s.has(4*9+1, 1))
+}
+
+func TestSwordfish(t *testing.T) {
+	s := newEmptySolver(t)
+	keepInRow(s, 0, 1, 0, 4)
+	keepInRow(s, 3, 1, 4, 8)
+	keepInRow(s, 6, 1, 0, 8)
+
+	// No X-Wing exists, but a Swordfish does.
+	_, ok := s.fish(XWing, 2)
+	require.False(t, ok)
+	step, ok := s.fish(Swordfish, 3)
+	require.True(t, ok)
+	require.Len(t, step.Cells, 6)
+	// 1 is eliminated from the 3 columns in the other 6 rows.
+	require.Len(t, step.Eliminations, 18)
+}
+
+func TestXYWing(t *testing.T) {
+	s := newEmptySolver(t)
+	setCands(s, 0, 0, 1, 2) // Pivot
+	setCands(s, 0, 4, 1, 3) // Pincer
+	setCands(s, 4, 0, 2, 3) // Pincer
+
+	step, ok := s.xyWing()
+	require.True(t, ok)
+	require.Equal(t, Step{
+		Technique:    XYWing,
+		Eliminations: []Candidate{{Cell{4, 4}, 3}},
+		Cells:        []Cell{{0, 0}, {0, 4}, {4, 0}},
+	}, step)
+}
+
+func TestCombinations(t *testing.T) {
+	var combos [][]int
+	combinations(4, 2, func(combo []int) bool {
+		combos = append(combos, append([]int(nil), combo...))
+		return false
+	})
+	require.Equal(t, [][]int{{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3}}, combos)
+	require.False(t, combinations(2, 3, func([]int) bool { return true }))
+}
@@ -0,0 +1,122 @@
+package sudoku
+
+import "fmt"
+
+// SearchOrder represents the order in which the backtracking search fills vacant positions.
+type SearchOrder int
+
+// Search orders of the backtracking search.
+const (
+	// RowMajor fills vacant positions from left to right, top to bottom.
+	RowMajor SearchOrder = iota
+	// MinRemainingValues places every naked single (a position with one candidate), then guesses
+	// the vacant position with the fewest candidates, repeating after each guess.
+	MinRemainingValues
+)
+
+// searchOrderNames maps a SearchOrder to its name.
+var searchOrderNames = map[SearchOrder]string{
+	RowMajor:           "row_major",
+	MinRemainingValues: "min_remaining_values",
+}
+
+// String implements the Stringer interface for SearchOrder.
+func (o SearchOrder) String() string {
+	if name, ok := searchOrderNames[o]; ok {
+		return name
+	}
+	return fmt.Sprintf("SearchOrder(%d)", int(o))
+}
+
+// available returns the number of values that can be placed at the vacant row and col position,
+// and the smallest of them.
+func (p Puzzle) available(row, col PuzzleInt) (n int, first PuzzleInt) {
+	taken := p.taken(row, col)
+	for val := PuzzleInt(1); val <= PuzzleInt(len(p.Arr)); val++ {
+		if taken.Get(int(val)) == 0 {
+			if n == 0 {
+				first = val
+			}
+			n++
+		}
+	}
+	return n, first
+}
+
+// propagate places the naked singles of the puzzle until none are left, appending the placed
+// positions to trail. propagate returns the vacant position with the fewest candidates, where ok
+// is false if there are none. If a vacant position has no candidates, dead is true.
+func (p Puzzle) propagate(trail *[]Cell) (min Cell, ok, dead bool) {
+	for {
+		minCount, placed := 0, false
+		for row := PuzzleInt(0); row < PuzzleInt(len(p.Arr)); row++ {
+			for col := PuzzleInt(0); col < PuzzleInt(len(p.Arr)); col++ {
+				if p.Arr[row][col] != 0 {
+					continue
+				}
+				n, val := p.available(row, col)
+				switch {
+				case n == 0:
+					return Cell{}, false, true
+				case n == 1:
+					// Naked single, place it right away.
+					p.set(row, col, val)
+					*trail = append(*trail, Cell{row, col})
+					placed = true
+				case minCount == 0 || n < minCount:
+					min, minCount = Cell{row, col}, n
+				}
+			}
+		}
+		// Placing singles changes the candidates of other positions, so scan again.
+		if !placed {
+			return min, minCount != 0, false
+		}
+	}
+}
+
+// solveMRV searches for solutions in MinRemainingValues order, calling found on s for every
+// solution. solveMRV returns true when the search should stop, leaving the solution in place.
+func (p Puzzle) solveMRV(s *search) bool {
+	// Stop when canceled or over budget.
+	if s.visit() {
+		return true
+	}
+	var trail []Cell
+	// undo vacates the positions placed by propagation.
+	undo := func() {
+		for _, c := range trail {
+			p.unset(c.Row, c.Col, p.Arr[c.Row][c.Col])
+		}
+	}
+
+	min, ok, dead := p.propagate(&trail)
+	if dead {
+		undo()
+		return false
+	}
+	if !ok {
+		// No vacant position, a solution was found.
+		if s.found() {
+			return true
+		}
+		undo()
+		return false
+	}
+
+	// Guess every candidate of the position with the fewest candidates.
+	taken := p.taken(min.Row, min.Col)
+	for val := PuzzleInt(1); val <= PuzzleInt(len(p.Arr)); val++ {
+		if taken.Get(int(val)) == 1 {
+			continue
+		}
+		p.set(min.Row, min.Col, val)
+		if p.solveMRV(s) {
+			return true
+		}
+		p.unset(min.Row, min.Col, val)
+		s.Backtracks++
+	}
+	undo()
+	return false
+}
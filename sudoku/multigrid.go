@@ -0,0 +1,385 @@
+package sudoku
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Grid represents a grid of a multi-grid puzzle, with the top left position of its puzzle at the
+// Row and Col position of the board the grids are laid out on.
+type Grid struct {
+	Row    int    `json:"row"`
+	Col    int    `json:"col"`
+	Puzzle Puzzle `json:"puzzle"`
+}
+
+// MultiPuzzle represents a puzzle of overlapping grids, such as Samurai Sudoku, where a position
+// of the board shared by several grids holds the same value in each of them. A solution follows
+// the constraints of every grid at once.
+type MultiPuzzle struct {
+	Grids []Grid `json:"grids"`
+
+	// The dimensions of the board, and the grid positions at each position of the board, in row
+	// major order.
+	height, width int
+	cells         [][]gridCell
+}
+
+// gridCell represents a position of a grid of a multi-grid puzzle.
+type gridCell struct {
+	grid     int
+	row, col PuzzleInt
+}
+
+// Errors returned when constructing a multi-grid puzzle from malformed grids.
+var (
+	ErrNoGrids      = errors.New("multi-grid puzzle must have at least one grid")
+	ErrGridPosition = errors.New("grid position must not be negative")
+	ErrSharedCell   = errors.New("shared position holds different values in its grids")
+	ErrSamurai      = errors.New("samurai grids must have the same dimensions")
+)
+
+// NewMultiPuzzle constructs a multi-grid puzzle of the passed grids, tying together the positions
+// they share: the value of a shared position is placed in every grid where it is vacant, so the
+// underlying arrays of the grids are altered. An error wrapping ErrNoGrids, ErrNoRows,
+// ErrGridPosition, ErrSharedCell, or ErrValueRange is returned if the grids are malformed.
+func NewMultiPuzzle(grids ...Grid) (MultiPuzzle, error) {
+	if len(grids) == 0 {
+		return MultiPuzzle{}, ErrNoGrids
+	}
+	m := MultiPuzzle{Grids: grids}
+	for i, g := range grids {
+		if len(g.Puzzle.Arr) == 0 {
+			return MultiPuzzle{}, fmt.Errorf("%w: grid %d", ErrNoRows, i)
+		}
+		if g.Row < 0 || g.Col < 0 {
+			return MultiPuzzle{}, fmt.Errorf("%w: grid %d at row %d, column %d", ErrGridPosition, i, g.Row, g.Col)
+		}
+		if n := g.Row + len(g.Puzzle.Arr); n > m.height {
+			m.height = n
+		}
+		if n := g.Col + len(g.Puzzle.Arr); n > m.width {
+			m.width = n
+		}
+	}
+
+	m.cells = make([][]gridCell, m.height*m.width)
+	for i, g := range grids {
+		for row := range g.Puzzle.Arr {
+			for col := range g.Puzzle.Arr[row] {
+				pos := (g.Row+row)*m.width + g.Col + col
+				m.cells[pos] = append(m.cells[pos], gridCell{i, PuzzleInt(row), PuzzleInt(col)})
+			}
+		}
+	}
+
+	// Tie the values of the shared positions together.
+	for pos, cells := range m.cells {
+		if len(cells) < 2 {
+			continue
+		}
+		val, side := PuzzleInt(0), m.side(cells)
+		for _, c := range cells {
+			v := m.value(c)
+			if v != 0 && val != 0 && v != val {
+				return MultiPuzzle{}, fmt.Errorf("%w: %d and %d at row %d, column %d of the board", ErrSharedCell,
+					val, v, pos/m.width, pos%m.width)
+			}
+			if v != 0 {
+				val = v
+			}
+		}
+		if int(val) > side {
+			return MultiPuzzle{}, fmt.Errorf("%w: %d at row %d, column %d of the board", ErrValueRange, val,
+				pos/m.width, pos%m.width)
+		}
+		if val != 0 {
+			for _, c := range cells {
+				if m.value(c) == 0 {
+					m.Grids[c.grid].Puzzle.set(c.row, c.col, val)
+				}
+			}
+		}
+	}
+	return m, nil
+}
+
+// NewSamurai constructs a Samurai Sudoku puzzle of five grids of the same dimensions, such as
+// 9x9, where the center grid shares each of its corner boxes with one of the four other grids.
+// The grids are ordered top left, top right, center, bottom left, then bottom right (see
+// NewMultiPuzzle). An error wrapping ErrSamurai is returned if the grids differ in dimensions.
+func NewSamurai(grids [5]Puzzle) (MultiPuzzle, error) {
+	side := len(grids[0].Arr)
+	h, w := grids[0].BoxDimensions()
+	for i, g := range grids {
+		if gh, gw := g.BoxDimensions(); len(g.Arr) != side || gh != h || gw != w {
+			return MultiPuzzle{}, fmt.Errorf("%w: grid %d is %dx%d with %dx%d boxes, expected %dx%d with %dx%d boxes",
+				ErrSamurai, i, len(g.Arr), len(g.Arr), gh, gw, side, side, h, w)
+		}
+	}
+	// The center grid overlaps the other grids by a box.
+	row, col := side-int(h), side-int(w)
+	return NewMultiPuzzle(
+		Grid{Row: 0, Col: 0, Puzzle: grids[0]},
+		Grid{Row: 0, Col: 2 * col, Puzzle: grids[1]},
+		Grid{Row: row, Col: col, Puzzle: grids[2]},
+		Grid{Row: 2 * row, Col: 0, Puzzle: grids[3]},
+		Grid{Row: 2 * row, Col: 2 * col, Puzzle: grids[4]},
+	)
+}
+
+// value returns the value of the grid position c.
+func (m MultiPuzzle) value(c gridCell) PuzzleInt {
+	return m.Grids[c.grid].Puzzle.Arr[c.row][c.col]
+}
+
+// side returns the side of the smallest of the grids of cells, the largest value of the position.
+func (m MultiPuzzle) side(cells []gridCell) int {
+	side := 0
+	for i, c := range cells {
+		if n := len(m.Grids[c.grid].Puzzle.Arr); i == 0 || n < side {
+			side = n
+		}
+	}
+	return side
+}
+
+// taken returns the values that cannot be placed at the vacant board position of cells, the
+// union of those taken in each of its grids.
+func (m MultiPuzzle) taken(cells []gridCell) bitSet {
+	var bs bitSet
+	for _, c := range cells {
+		taken := m.Grids[c.grid].Puzzle.taken(c.row, c.col)
+		bs.Union(&taken)
+	}
+	return bs
+}
+
+// set places val at the board position of cells, in each of its grids.
+func (m MultiPuzzle) set(cells []gridCell, val PuzzleInt) {
+	for _, c := range cells {
+		m.Grids[c.grid].Puzzle.set(c.row, c.col, val)
+	}
+}
+
+// unset vacates the board position of cells, which holds val, in each of its grids.
+func (m MultiPuzzle) unset(cells []gridCell, val PuzzleInt) {
+	for _, c := range cells {
+		m.Grids[c.grid].Puzzle.unset(c.row, c.col, val)
+	}
+}
+
+// Board returns the values of the board the grids are laid out on, where vacant positions and
+// positions outside of every grid are 0.
+func (m MultiPuzzle) Board() [][]PuzzleInt {
+	board := make([][]PuzzleInt, m.height)
+	for row := range board {
+		board[row] = make([]PuzzleInt, m.width)
+		for col := range board[row] {
+			if cells := m.cells[row*m.width+col]; len(cells) != 0 {
+				board[row][col] = m.value(cells[0])
+			}
+		}
+	}
+	return board
+}
+
+// Solve solves every grid of the puzzle at once, like Puzzle.Solve. Solve returns true when the
+// puzzle is successfully solved, otherwise, the puzzle was unsolvable.
+func (m MultiPuzzle) Solve() bool {
+	_, err := m.SolveContext(context.Background())
+	return err == nil
+}
+
+// SolveContext solves every grid of the puzzle at once, stopping the search once ctx is done.
+// Vacant positions are filled in MinRemainingValues order across the board, following the
+// constraints of every grid of each position, while the solvers and budgets of the grids are not
+// used. Puzzles with a grid with conflicting values (see Puzzle.Validate) are rejected before
+// searching.
+//
+// SolveContext returns the statistics of the search, and an error wrapping ErrUnsolvable or the
+// error of ctx when no solution was found, in which case the vacant positions are left vacant.
+func (m MultiPuzzle) SolveContext(ctx context.Context) (Result, error) {
+	res, err := m.run(ctx, 1)
+	if err == nil && res.Solutions == 0 {
+		err = ErrUnsolvable
+	}
+	return res, err
+}
+
+// Solved returns a solved copy of the puzzle, with its own grids, leaving the puzzle and the
+// underlying arrays of its grids untouched. ok is false when the copy could not be solved (see
+// Solve).
+func (m MultiPuzzle) Solved() (solution MultiPuzzle, ok bool) {
+	c := m.clone()
+	return c, c.Solve()
+}
+
+// CountSolutions counts the solutions of the puzzle, stopping once limit solutions are found,
+// unless limit is not positive. The puzzle is left untouched.
+func (m MultiPuzzle) CountSolutions(limit int) int {
+	res, _ := m.clone().run(context.Background(), limit)
+	return res.Solutions
+}
+
+// HasUniqueSolution returns whether or not the puzzle has exactly one solution.
+func (m MultiPuzzle) HasUniqueSolution() bool {
+	return m.CountSolutions(2) == 1
+}
+
+// clone returns a deep copy of the puzzle, with copies of its grids, such that altering the copy
+// does not affect the original puzzle.
+func (m MultiPuzzle) clone() MultiPuzzle {
+	c := m
+	c.Grids = make([]Grid, len(m.Grids))
+	for i, g := range m.Grids {
+		g.Puzzle = g.Puzzle.clone()
+		c.Grids[i] = g
+	}
+	return c
+}
+
+// run searches the solutions of the puzzle, up to limit, timing the search. If the search is
+// interrupted, the positions it filled are vacated.
+func (m MultiPuzzle) run(ctx context.Context, limit int) (Result, error) {
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+	for _, g := range m.Grids {
+		if len(g.Puzzle.Validate()) != 0 {
+			return Result{}, nil
+		}
+	}
+	// Keep the vacant positions to restore them after an interruption.
+	var vacant []int
+	for pos, cells := range m.cells {
+		if len(cells) != 0 && m.value(cells[0]) == 0 {
+			vacant = append(vacant, pos)
+		}
+	}
+
+	start := time.Now()
+	s := search{limit: limit, ctx: ctx}
+	m.solve(&s)
+	s.Elapsed = time.Since(start)
+
+	if s.err != nil {
+		for _, pos := range vacant {
+			for _, c := range m.cells[pos] {
+				m.Grids[c.grid].Puzzle.Arr[c.row][c.col] = 0
+			}
+		}
+		for _, g := range m.Grids {
+			g.Puzzle.populate()
+		}
+		return s.Result, s.err
+	}
+	return s.Result, nil
+}
+
+// solve recursively fills the vacant board position with the fewest candidates, calling found on
+// s for every solution. solve returns true when the search should stop, leaving the solution in
+// place.
+func (m MultiPuzzle) solve(s *search) bool {
+	// Stop when canceled or over budget.
+	if s.visit() {
+		return true
+	}
+
+	// Find the vacant position with the fewest candidates, failing early on positions without any.
+	min, minCount := -1, 0
+	for pos, cells := range m.cells {
+		if len(cells) == 0 || m.value(cells[0]) != 0 {
+			continue
+		}
+		taken, n := m.taken(cells), 0
+		for val := 1; val <= m.side(cells); val++ {
+			if taken.Get(val) == 0 {
+				n++
+			}
+		}
+		if n == 0 {
+			return false
+		}
+		if min == -1 || n < minCount {
+			min, minCount = pos, n
+		}
+	}
+	if min == -1 {
+		// No vacant position, a solution was found.
+		return s.found()
+	}
+
+	// Try every candidate of the position, recurse, and backtrack.
+	cells := m.cells[min]
+	taken := m.taken(cells)
+	for val := PuzzleInt(1); int(val) <= m.side(cells); val++ {
+		if taken.Get(int(val)) == 1 {
+			continue
+		}
+		m.set(cells, val)
+		if m.solve(s) {
+			return true
+		}
+		m.unset(cells, val)
+		s.Backtracks++
+	}
+	return false
+}
+
+// Pretty returns a formatted string representation of the board for human readability, where
+// the boxes of the grids are separated by borders, like jigsaw puzzles (see Puzzle.Pretty), and
+// positions outside of every grid are left blank. Shared positions are drawn with the boxes of
+// the first of their grids.
+func (m MultiPuzzle) Pretty() string {
+	// Number the boxes of every grid apart.
+	offsets := make([]int, len(m.Grids))
+	for i := 1; i < len(m.Grids); i++ {
+		offsets[i] = offsets[i-1] + len(m.Grids[i-1].Puzzle.units)
+	}
+	return prettyBorders(m.Board(), func(row, col int) int {
+		cells := m.cells[row*m.width+col]
+		if len(cells) == 0 {
+			return -1
+		}
+		c := cells[0]
+		p := m.Grids[c.grid].Puzzle
+		for _, u := range p.cellUnits[p.cellIndex(c.row, c.col)] {
+			if p.units[u].Type == UnitBox {
+				return offsets[c.grid] + u
+			}
+		}
+		return offsets[c.grid]
+	})
+}
+
+// String implements the Stringer interface for MultiPuzzle by encoding the board into JSON, like
+// Puzzle.String.
+func (m MultiPuzzle) String() string {
+	b, err := json.Marshal(m.Board())
+	if err != nil {
+		panic("could not encode multi-grid puzzle into json")
+	}
+	return string(b)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for MultiPuzzle, decoding its grids,
+// with their puzzles in the line format (see Puzzle.MarshalText), and tying them together (see
+// NewMultiPuzzle).
+func (m *MultiPuzzle) UnmarshalJSON(b []byte) error {
+	var v struct {
+		Grids []Grid `json:"grids"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	puzzle, err := NewMultiPuzzle(v.Grids...)
+	if err != nil {
+		return err
+	}
+	*m = puzzle
+	return nil
+}
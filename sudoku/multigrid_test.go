@@ -0,0 +1,179 @@
+package sudoku
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// twodoku returns a multi-grid puzzle of two 4x4 grids sharing a box, with 3456 solutions when
+// empty.
+func twodoku(t *testing.T, a, b [][]PuzzleInt) MultiPuzzle {
+	first, err := NewPuzzle(a)
+	require.NoError(t, err)
+	second, err := NewPuzzle(b)
+	require.NoError(t, err)
+	m, err := NewMultiPuzzle(Grid{Row: 0, Col: 0, Puzzle: first}, Grid{Row: 2, Col: 2, Puzzle: second})
+	require.NoError(t, err)
+	return m
+}
+
+func TestSamurai(t *testing.T) {
+	lines := [5]string{
+		"31...7.9.....89...7..1.......2...98..9....571..8.5..6...42..........4..3.......5.",
+		".5..4.78.....8...4.8...725.4.3..2...............4.3.215....1.......9..........6.5",
+		"......5....3.8.....5.......1....59....49...5....67....................27...3.8...",
+		"4.67.......91.......83...................54.27......6...2..8.......1...49.....1.5",
+		"...1......27.....8...4.........5.893..3...6......9..1..3.61.7..4....7..67...4.3..",
+	}
+	solutions := [5]string{
+		"316427895245689137789135246152763984693842571478951362534278619861594723927316458",
+		"351246789762589134984137256413752968625918347897463521578621493146395872239874615",
+		"619234578723589146458167239172845963864913752395672481231756894586491327947328615",
+		"456789231379124586128356947241863759863975412795241368512498673637512894984637125",
+		"894123567327569148615478239162754893943281675578396412239615784451837926786942351",
+	}
+	var grids [5]Puzzle
+	for i, line := range lines {
+		puzzle, err := ParseLine(line)
+		require.NoError(t, err)
+		grids[i] = puzzle
+	}
+	m, err := NewSamurai(grids)
+	require.NoError(t, err)
+	require.True(t, m.HasUniqueSolution())
+
+	solution, ok := m.Solved()
+	require.True(t, ok)
+	for i, g := range solution.Grids {
+		text, err := g.Puzzle.MarshalText()
+		require.NoError(t, err)
+		require.Equal(t, solutions[i], string(text))
+		require.Empty(t, g.Puzzle.Validate())
+	}
+	// The puzzle itself is left untouched.
+	text, err := m.Grids[2].Puzzle.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, lines[2], string(text))
+
+	_, err = NewSamurai([5]Puzzle{grids[0], grids[1], grids[2], grids[3], {}})
+	require.ErrorIs(t, err, ErrSamurai)
+}
+
+func TestMultiPuzzleShared(t *testing.T) {
+	a, b := emptyArr(4), emptyArr(4)
+	a[3][3], b[1][0] = 4, 3
+	m := twodoku(t, a, b)
+	// Shared clues are placed in every grid.
+	require.Equal(t, PuzzleInt(4), m.Grids[1].Puzzle.Arr[1][1])
+	require.Equal(t, PuzzleInt(3), m.Grids[0].Puzzle.Arr[3][2])
+	require.Equal(t, [][]PuzzleInt{
+		{0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0},
+		{0, 0, 3, 4, 0, 0},
+		{0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0},
+	}, m.Board())
+
+	require.Equal(t, 3456, twodoku(t, emptyArr(4), emptyArr(4)).CountSolutions(0))
+	require.Equal(t, 2, twodoku(t, emptyArr(4), emptyArr(4)).CountSolutions(2))
+
+	// A solution follows both grids at once.
+	require.True(t, m.Solve())
+	for _, g := range m.Grids {
+		require.Empty(t, g.Puzzle.Validate())
+		require.NotContains(t, g.Puzzle.String(), "0")
+	}
+	require.Equal(t, m.Grids[0].Puzzle.Arr[2][2], m.Grids[1].Puzzle.Arr[0][0])
+	require.Equal(t, m.Grids[0].Puzzle.Arr[3][3], m.Grids[1].Puzzle.Arr[1][1])
+
+}
+
+func TestMultiPuzzleContext(t *testing.T) {
+	m := twodoku(t, emptyArr(4), emptyArr(4))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := m.SolveContext(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, emptyArr(6), m.Board())
+
+	// Grids with conflicting values are rejected.
+	a := emptyArr(4)
+	a[0][0], a[0][1] = 1, 1
+	m = twodoku(t, a, emptyArr(4))
+	_, err = m.SolveContext(context.Background())
+	require.ErrorIs(t, err, ErrUnsolvable)
+}
+
+func TestMultiPuzzleJSON(t *testing.T) {
+	a, b := emptyArr(4), emptyArr(4)
+	a[0][0], b[3][3] = 1, 2
+	m := twodoku(t, a, b)
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"grids": [
+		{"row": 0, "col": 0, "puzzle": "1..............."},
+		{"row": 2, "col": 2, "puzzle": "...............2"}
+	]}`, string(data))
+
+	var decoded MultiPuzzle
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, m.Board(), decoded.Board())
+	require.True(t, decoded.Solve())
+
+	err = json.Unmarshal([]byte(`{"grids": [
+		{"row": 0, "col": 0, "puzzle": "...............1"},
+		{"row": 3, "col": 3, "puzzle": "2..............."}
+	]}`), &decoded)
+	require.ErrorIs(t, err, ErrSharedCell)
+}
+
+func TestMultiPuzzlePretty(t *testing.T) {
+	a, b := emptyArr(4), emptyArr(4)
+	a[0][0], a[3][3], b[3][3] = 1, 4, 2
+	m := twodoku(t, a, b)
+	require.Equal(t, ""+
+		"1  |        \n"+
+		"            \n"+
+		"   |        \n"+
+		"- - - -     \n"+
+		"   |   |    \n"+
+		"            \n"+
+		"   |  4|    \n"+
+		"    - - - - \n"+
+		"       |    \n"+
+		"            \n"+
+		"       |  2 \n", m.Pretty())
+}
+
+func TestMultiPuzzleErrors(t *testing.T) {
+	puzzle, err := NewPuzzle(emptyArr(4))
+	require.NoError(t, err)
+	clue := func(row, col int, val PuzzleInt, side int) Puzzle {
+		arr := emptyArr(side)
+		arr[row][col] = val
+		p, err := NewPuzzle(arr)
+		require.NoError(t, err)
+		return p
+	}
+	testCases := []struct {
+		name  string
+		grids []Grid
+		err   error
+	}{
+		{"no grids", nil, ErrNoGrids},
+		{"no rows", []Grid{{Puzzle: puzzle}, {Row: 2, Col: 2}}, ErrNoRows},
+		{"negative", []Grid{{Row: -1, Puzzle: puzzle}}, ErrGridPosition},
+		{"shared", []Grid{{Puzzle: clue(2, 2, 1, 4)}, {Row: 2, Col: 2, Puzzle: clue(0, 0, 2, 4)}}, ErrSharedCell},
+		{"out of range", []Grid{{Puzzle: clue(0, 0, 9, 9)}, {Puzzle: puzzle}}, ErrValueRange},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewMultiPuzzle(tc.grids...)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
@@ -1,20 +1,89 @@
 package sudoku
 
-// puzzleOption represents a function that, when called, configures a puzzle.
-type puzzleOption func(*Puzzle)
+import "time"
 
-// WithBoxDimensions sets the dimensions of a box in a Sudoku puzzle.
-func WithBoxDimensions(height, width PuzzleInt) puzzleOption {
+// PuzzleOption represents a function that, when called, configures a puzzle.
+type PuzzleOption func(*Puzzle)
+
+// WithBoxDimensions sets the dimensions of a box in a Sudoku puzzle. The dimensions must
+// evenly divide the side length of the puzzle and multiply to it, otherwise NewPuzzle returns
+// ErrBoxDimensions. A zero dimension spans the entire side of the puzzle.
+func WithBoxDimensions(height, width PuzzleInt) PuzzleOption {
 	return func(p *Puzzle) {
 		// The product of the height and width should equal the side length of a puzzle.
+		p.boxHeight, p.boxWidth = height, width
+	}
+}
 
-		// Ensure the dimensions do not exceed the actual puzzle.
-		if height > PuzzleInt(len(p.Arr)) {
-			height = PuzzleInt(len(p.Arr))
-		}
-		if width > PuzzleInt(len(p.Arr[0])) {
-			width = PuzzleInt(len(p.Arr[0]))
+// WithSearchOrder sets the order in which the backtracking search fills vacant positions. The
+// default is RowMajor.
+func WithSearchOrder(order SearchOrder) PuzzleOption {
+	return func(p *Puzzle) {
+		p.searchOrder = order
+	}
+}
+
+// WithSolver sets the search engine used to solve the puzzle. The default is Backtracking.
+func WithSolver(s Solver) PuzzleOption {
+	return func(p *Puzzle) {
+		p.solver = s
+	}
+}
+
+// WithMaxNodes limits the number of nodes of the search tree Solve, SolveContext, and the
+// counting of solutions may visit, after which the search fails with ErrBudgetExceeded. Zero, the
+// default, is unlimited. The limit is honoured by the built-in solvers.
+func WithMaxNodes(n int) PuzzleOption {
+	return func(p *Puzzle) {
+		p.maxNodes = n
+	}
+}
+
+// WithMaxDuration limits the time Solve, SolveContext, and the counting of solutions may search
+// for, after which the search fails with ErrBudgetExceeded. Zero, the default, is unlimited. The
+// limit is honoured by the built-in solvers.
+func WithMaxDuration(d time.Duration) PuzzleOption {
+	return func(p *Puzzle) {
+		p.maxDuration = d
+	}
+}
+
+// WithDiagonals adds the two main diagonals of a puzzle as units, which must hold every value
+// once, as in X-Sudoku (see Diagonals).
+func WithDiagonals() PuzzleOption {
+	return WithConstraints(Diagonals)
+}
+
+// WithRegions replaces the boxes of a puzzle with the regions of a jigsaw puzzle, where
+// regions[row][col] is the region of the row and col position, from 0 to the side length
+// exclusive. Every region must have as many cells as the side length, connected horizontally or
+// vertically, otherwise NewPuzzle returns ErrRegions. Box dimensions are ignored, as regions need
+// not be rectangular. regions should not be altered after puzzle creation.
+func WithRegions(regions [][]PuzzleInt) PuzzleOption {
+	return func(p *Puzzle) {
+		p.regions = regions
+	}
+}
+
+// WithCages adds the cages of a Killer Sudoku puzzle, whose values must add up to the sum of
+// their cage without repeating (see Cage). Cages may not overlap, and every solver prunes values
+// that leave a cage unable to add up to its sum. If a cage is malformed, NewPuzzle returns
+// ErrCage.
+func WithCages(cages ...Cage) PuzzleOption {
+	return func(p *Puzzle) {
+		p.cages = append(p.cages, cages...)
+	}
+}
+
+// WithConstraints adds variant rules to the rows, columns, and boxes of a puzzle (see
+// Constraint). Constraints the puzzle already has are skipped. If the units of a constraint are
+// malformed, NewPuzzle returns ErrUnit.
+func WithConstraints(cs ...Constraint) PuzzleOption {
+	return func(p *Puzzle) {
+		for _, c := range cs {
+			if !p.hasConstraint(c) {
+				p.constraints = append(p.constraints, c)
+			}
 		}
-		p.boxHeight, p.boxWidth = height, width
 	}
 }
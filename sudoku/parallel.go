@@ -0,0 +1,185 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// parallel implements the solver returned by NewParallel.
+type parallel struct {
+	depth, workers int
+}
+
+// NewParallel returns a Solver that splits the search tree into the subtrees below the first depth
+// vacant positions (in row major order), and backtracks through them on a pool of at most workers
+// goroutines, each subtree on its own copy of the puzzle. The search order of the puzzle is used
+// within every subtree (see WithSearchOrder). Once a solution is found, or the limit of a count
+// is reached, the remaining workers are canceled.
+//
+// A depth below 1 splits at the first vacant position, and fewer than 1 workers default to
+// GOMAXPROCS. The number of subtrees grows exponentially with depth. The node budget of the puzzle
+// (see WithMaxNodes) applies to each subtree, while the duration budget applies to the whole
+// search. When a puzzle has several solutions, the one found first is not necessarily the one
+// found by Backtracking.
+func NewParallel(depth, workers int) Solver {
+	if depth < 1 {
+		depth = 1
+	}
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return parallel{depth: depth, workers: workers}
+}
+
+// Solve implements the Solver interface for parallel.
+func (ps parallel) Solve(ctx context.Context, p *Puzzle) (Result, error) {
+	if len(p.Validate()) != 0 {
+		return Result{}, ErrUnsolvable
+	}
+	res, solution, err := ps.run(ctx, *p, 1, budget{p.maxNodes, p.maxDuration})
+	if err != nil {
+		return res, err
+	}
+	if solution == nil {
+		return res, ErrUnsolvable
+	}
+	// Copy the solution of the subtree into the puzzle.
+	for row := range p.Arr {
+		for col, val := range p.Arr[row] {
+			if val == 0 {
+				p.set(PuzzleInt(row), PuzzleInt(col), solution.Arr[row][col])
+			}
+		}
+	}
+	return res, nil
+}
+
+// Count implements the Counter interface for parallel.
+func (ps parallel) Count(ctx context.Context, p *Puzzle, limit int) (Result, error) {
+	if len(p.Validate()) != 0 {
+		return Result{}, nil
+	}
+	res, _, err := ps.run(ctx, *p, limit, budget{p.maxNodes, p.maxDuration})
+	return res, err
+}
+
+// run searches the subtrees of p on the worker pool within b, stopping every worker once limit
+// solutions are found, unless limit is not positive. The subtree holding the first solution
+// found is returned, if any. p is left untouched.
+func (ps parallel) run(ctx context.Context, p Puzzle, limit int, b budget) (Result, *Puzzle, error) {
+	if err := ctx.Err(); err != nil {
+		return Result{}, nil, err
+	}
+	start := time.Now()
+	var deadline time.Time
+	if b.maxDuration > 0 {
+		deadline = start.Add(b.maxDuration)
+	}
+
+	var res Result
+	subtrees := split(p.clone(), ps.depth, &res)
+
+	// Workers are canceled once the search is over, or when ctx is done.
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex // Guards res, solution, and budgetErr
+		solution  *Puzzle
+		budgetErr error
+		total     int64 // Solutions found across workers
+	)
+	jobs := make(chan Puzzle)
+	for i := 0; i < ps.workers && i < len(subtrees); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for sub := range jobs {
+				s := search{limit: limit, total: &total, ctx: workerCtx, budget: b, deadline: deadline}
+				backtracking{}.search(sub, &s)
+
+				mu.Lock()
+				res.Nodes += s.Nodes
+				res.Backtracks += s.Backtracks
+				if s.err == nil && s.Solutions != 0 && limit == 1 && solution == nil {
+					// The search stopped at the solution, keep it.
+					sub := sub
+					solution = &sub
+				}
+				if errors.Is(s.err, ErrBudgetExceeded) && budgetErr == nil {
+					budgetErr = s.err
+				}
+				mu.Unlock()
+
+				// Stop every worker once the limit is reached, or the budget is exceeded.
+				if reached(&total, limit) || errors.Is(s.err, ErrBudgetExceeded) {
+					cancel()
+				}
+			}
+		}()
+	}
+
+	// Feed the subtrees to the workers, until they are canceled.
+feed:
+	for _, sub := range subtrees {
+		select {
+		case jobs <- sub:
+		case <-workerCtx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	res.Solutions = int(atomic.LoadInt64(&total))
+	if limit > 0 && res.Solutions > limit {
+		res.Solutions = limit
+	}
+	res.Elapsed = time.Since(start)
+	if reached(&total, limit) {
+		return res, solution, nil
+	}
+	// The search was interrupted before the limit was reached.
+	if err := ctx.Err(); err != nil {
+		return res, nil, err
+	}
+	if budgetErr != nil {
+		return res, nil, budgetErr
+	}
+	return res, solution, nil
+}
+
+// reached returns whether or not total is at least a positive limit.
+func reached(total *int64, limit int) bool {
+	return limit > 0 && atomic.LoadInt64(total) >= int64(limit)
+}
+
+// split returns copies of p, with every combination of possible values at its first depth
+// vacant positions, in row major order. The nodes visited are recorded in res.
+func split(p Puzzle, depth int, res *Result) []Puzzle {
+	var subtrees []Puzzle
+	var walk func(row, col PuzzleInt, depth int)
+	walk = func(row, col PuzzleInt, depth int) {
+		res.Nodes++
+		row, col, ok := p.nextEmptyPos(row, col)
+		if !ok || depth == 0 {
+			subtrees = append(subtrees, p.clone())
+			return
+		}
+		for val := PuzzleInt(1); val <= PuzzleInt(len(p.Arr)); val++ {
+			if !p.isValidPos(row, col, val) {
+				continue
+			}
+			p.set(row, col, val)
+			walk(row, col, depth-1)
+			p.unset(row, col, val)
+		}
+	}
+	walk(0, 0, depth)
+	return subtrees
+}
@@ -0,0 +1,73 @@
+package sudoku
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewParallel(t *testing.T) {
+	require.Equal(t, parallel{depth: 3, workers: 2}, NewParallel(3, 2))
+	require.Equal(t, parallel{depth: 1, workers: runtime.GOMAXPROCS(0)}, NewParallel(0, 0))
+}
+
+func TestParallelCancelsWorkers(t *testing.T) {
+	// An empty puzzle has far too many solutions to count, so the search only ends if the
+	// workers are canceled.
+	solver := NewParallel(2, 4)
+	puzzle, err := NewPuzzle(emptyArr(9), WithSolver(solver))
+	require.NoError(t, err)
+	require.Equal(t, 5, puzzle.CountSolutions(5))
+
+	res, err := puzzle.SolveContext(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, res.Solutions)
+	require.Empty(t, puzzle.Validate())
+	_, _, ok := puzzle.nextEmptyPos(0, 0)
+	require.False(t, ok)
+
+	// Canceled by the context, as the count is unlimited.
+	puzzle, err = NewPuzzle(emptyArr(9))
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = solver.(Counter).Count(ctx, &puzzle, 0)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestParallelBudget(t *testing.T) {
+	// Needs guessing, so every subtree visits more than 10 nodes.
+	arr := lineArr("800400910003000000000003004000001040058000700070006800000002000000000160910060500", 9)
+	puzzle, err := NewPuzzle(copyArr(arr), WithSolver(NewParallel(1, 4)), WithMaxNodes(10))
+	require.NoError(t, err)
+	res, err := puzzle.SolveContext(context.Background())
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+	require.Zero(t, res.Solutions)
+	require.Equal(t, arr, puzzle.Arr)
+}
+
+func TestSplit(t *testing.T) {
+	puzzle, err := NewPuzzle([][]PuzzleInt{
+		{1, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	})
+	require.NoError(t, err)
+
+	var res Result
+	subtrees := split(puzzle, 2, &res)
+	// 3 values at the first vacant position, then 2 at the second.
+	require.Len(t, subtrees, 3*2)
+	for _, sub := range subtrees {
+		require.Empty(t, sub.Validate())
+		require.NotZero(t, sub.Arr[0][1])
+		require.NotZero(t, sub.Arr[0][2])
+	}
+	require.Equal(t, 1+3+3*2, res.Nodes)
+	// The puzzle is untouched.
+	require.Zero(t, puzzle.Arr[0][1])
+}
@@ -1,9 +1,14 @@
 /*
-Package sudoku implements a flexible recursive backtracking Sudoku puzzle solver library.
+Package sudoku implements a flexible recursive backtracking Sudoku puzzle solver library. Other
+search engines, such as Dancing Links, may be plugged in (see Solver).
 
 The recommended usage is:
 	arr := [][]PuzzleInt{...}
-	puzzle := sudoku.NewPuzzle(arr) // arr should not be edited anymore
+	puzzle, err := sudoku.NewPuzzle(arr) // arr is the storage of puzzle, it should not be edited anymore
+	if err != nil {
+		fmt.Println("Malformed puzzle:", err)
+		return
+	}
 
 	if solved := puzzle.Solve(); solved {
 		fmt.Println("Solved!")
@@ -13,14 +18,27 @@ The recommended usage is:
 	// Read the value of puzzle.Arr or arr after solve.
 	...
 	fmt.Println(puzzle.Pretty())
+
+Solve fills arr in place. To keep the clues alongside the solution, solve a copy instead, which
+leaves puzzle and arr untouched:
+	solution, ok := puzzle.Solved()
+	if !ok {
+		fmt.Println("Could not solve (invalid puzzle).")
+		return
+	}
+	fmt.Println(puzzle.Pretty())   // The clues
+	fmt.Println(solution.Pretty()) // The solution
 */
 package sudoku
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"strings"
+	"time"
 )
 
 // PuzzleInt reprents the integer type used in the puzzle.
@@ -30,38 +48,62 @@ type PuzzleInt = uint16
 type Puzzle struct {
 	Arr                 [][]PuzzleInt
 	boxHeight, boxWidth PuzzleInt
+	searchOrder         SearchOrder
+	solver              Solver
+	maxNodes            int
+	maxDuration         time.Duration
+
+	// The units of the constraints, the units of each cell, and a bitset of the values in each
+	// unit (see Constraint).
+	constraints []Constraint
+	units       []unit
+	cellUnits   [][]int
+	unitVals    []bitSet
+
+	// The region of each cell of jigsaw puzzles, or nil for puzzles with boxes (see WithRegions).
+	regions [][]PuzzleInt
 
-	// Acts as a bitset for the values in a row (rowVals), column (colVals), or box (boxVals).
-	rowVals, colVals []bitSet
-	boxVals          [][]bitSet
+	// The cages of Killer puzzles, and the cage of each cell (-1 for none), or nil without cages
+	// (see WithCages).
+	cages     []Cage
+	cellCages []int
 }
 
+// Errors returned when constructing a puzzle from a malformed matrix.
+var (
+	ErrNoRows        = errors.New("puzzle must have at least one row")
+	ErrRaggedRows    = errors.New("all puzzle rows must have a uniform number of columns")
+	ErrNotSquare     = errors.New("puzzle must be a square")
+	ErrTooLarge      = errors.New("puzzle side exceeds the range of PuzzleInt")
+	ErrBoxDimensions = errors.New("box dimensions must evenly divide the puzzle side and multiply to it")
+	ErrValueRange    = errors.New("puzzle value exceeds the side length")
+)
+
 // NewPuzzle constructs a new puzzle with the passed matrix. Options may be passed
 // to further configure a puzzle. arr should not be altered after puzzle creation as
 // it is used as the underlying array for the puzzle. New puzzles should not reuse
-// an old Puzzle struct, but should construct a new Puzzle.
-func NewPuzzle(arr [][]PuzzleInt, opts ...puzzleOption) Puzzle {
-	// TODO: compare puzzle array size with max of PuzzleInt.
-
+// an old Puzzle struct, but should construct a new Puzzle. An error wrapping one of
+// the Err* values is returned if the matrix or options are malformed.
+func NewPuzzle(arr [][]PuzzleInt, opts ...PuzzleOption) (Puzzle, error) {
 	// Validate number of rows.
 	rows := len(arr)
 	if rows == 0 {
-		panic("invalid number of puzzle rows (0)")
+		return Puzzle{}, ErrNoRows
 	}
-	// Validate number of columns.
+	// Ensure all rows have the same number of columns.
 	cols := len(arr[0])
-	if cols == 0 {
-		panic("invalid number of puzzle columns (0)")
+	for i, row := range arr {
+		if len(row) != cols {
+			return Puzzle{}, fmt.Errorf("%w: row %d has %d columns, expected %d", ErrRaggedRows, i, len(row), cols)
+		}
 	}
 	// Ensure the puzzle is a square.
 	if rows != cols {
-		panic("invalid puzzle, must be a square")
+		return Puzzle{}, fmt.Errorf("%w: %d rows and %d columns", ErrNotSquare, rows, cols)
 	}
-	// Ensure all rows have the same number of columns.
-	for _, row := range arr {
-		if len(row) != cols {
-			panic("all rows must have a uniform number of columns")
-		}
+	// Ensure every value of the puzzle can be represented by a PuzzleInt.
+	if rows > math.MaxUint16 {
+		return Puzzle{}, fmt.Errorf("%w: side %d is larger than %d", ErrTooLarge, rows, math.MaxUint16)
 	}
 
 	puzzle := Puzzle{
@@ -69,9 +111,9 @@ func NewPuzzle(arr [][]PuzzleInt, opts ...puzzleOption) Puzzle {
 		// Box dimensions default to the square root of each puzzle side.
 		boxHeight: PuzzleInt(math.Sqrt(float64(rows))),
 		boxWidth:  PuzzleInt(math.Sqrt(float64(cols))),
-
-		rowVals: make([]bitSet, rows),
-		colVals: make([]bitSet, cols),
+		solver:    Backtracking,
+		// Rows, columns, and boxes precede the constraints of the options.
+		constraints: []Constraint{Rows, Columns, Boxes},
 	}
 
 	// Apply options.
@@ -79,6 +121,10 @@ func NewPuzzle(arr [][]PuzzleInt, opts ...puzzleOption) Puzzle {
 		opt(&puzzle)
 	}
 
+	// Regions take the place of boxes, leaving a single box spanning the puzzle.
+	if puzzle.regions != nil {
+		puzzle.boxHeight, puzzle.boxWidth = PuzzleInt(rows), PuzzleInt(cols)
+	}
 	// Ensure non-zero box dimensions for division.
 	if puzzle.boxHeight == 0 {
 		puzzle.boxHeight = PuzzleInt(rows)
@@ -86,82 +132,72 @@ func NewPuzzle(arr [][]PuzzleInt, opts ...puzzleOption) Puzzle {
 	if puzzle.boxWidth == 0 {
 		puzzle.boxWidth = PuzzleInt(cols)
 	}
-
-	// Calculate number of boxes and its bitset.
-	// rows and cols should be divisible by boxHeight and boxWidth, respectively.
-	puzzle.boxVals = make([][]bitSet, rows/int(puzzle.boxHeight))
-	for boxRow := range puzzle.boxVals {
-		puzzle.boxVals[boxRow] = make([]bitSet, cols/int(puzzle.boxWidth))
+	// rows and cols must be divisible by boxHeight and boxWidth, respectively, and boxes must hold
+	// as many cells as a side, unless regions replace them.
+	if rows%int(puzzle.boxHeight) != 0 || cols%int(puzzle.boxWidth) != 0 ||
+		puzzle.regions == nil && int(puzzle.boxHeight)*int(puzzle.boxWidth) != rows {
+		return Puzzle{}, fmt.Errorf("%w: %dx%d boxes in a %dx%d puzzle", ErrBoxDimensions,
+			puzzle.boxHeight, puzzle.boxWidth, rows, cols)
 	}
 
-	return puzzle
-}
-
-// TODO: next 3 constraint functions have a pattern (like: get bitset and populate func)
-// see the pattern and make a file "constraints.go" where code can be reused
-
-// rowContains returns whether or not the row contains val.
-func (p Puzzle) rowContains(row, val PuzzleInt) bool {
-	bs := &p.rowVals[row]
-	// Populate the bitset if empty or val is 0 (0 is the value replaced during a solve).
-	if bs.Len() == 0 || val == 0 {
-		for _, n := range p.Arr[row] {
-			bs.Set(int(n), 1)
+	// Ensure all values are within the side length (0 represents an empty position).
+	for i, row := range arr {
+		for j, v := range row {
+			if int(v) > rows {
+				return Puzzle{}, fmt.Errorf("%w: %d at row %d, column %d", ErrValueRange, v, i, j)
+			}
 		}
 	}
-	// Return true if val is found in the set.
-	return bs.Get(int(val)) == 1
-}
 
-// colContains returns whether or not the col contains val.
-func (p Puzzle) colContains(col, val PuzzleInt) bool {
-	bs := &p.colVals[col]
-	// Populate the bitset if empty.
-	if bs.Len() == 0 || val == 0 {
-		for _, row := range p.Arr {
-			bs.Set(int(row[col]), 1)
-		}
+	// Replace the boxes with the regions, if any, and index the cages, if any, whose constraint
+	// follows every other one.
+	if err := puzzle.buildRegions(); err != nil {
+		return Puzzle{}, err
 	}
-	// Return true if val is found in the set.
-	return bs.Get(int(val)) == 1
-}
+	if err := puzzle.buildCages(); err != nil {
+		return Puzzle{}, err
+	}
+	// List the units of the constraints and their bitsets.
+	if err := puzzle.buildUnits(); err != nil {
+		return Puzzle{}, err
+	}
+	puzzle.populate()
 
-// boxIndex calculates the index of the current box.
-func (p Puzzle) boxIndex(row, col PuzzleInt) (boxRow, boxCol PuzzleInt) {
-	return row / p.boxHeight, col / p.boxWidth
+	return puzzle, nil
 }
 
-// boxContains returns whether or not the box contains val.
-func (p Puzzle) boxContains(row, col, val PuzzleInt) bool {
-	// Calculate the box's bitset index.
-	boxRow, boxCol := p.boxIndex(row, col)
-	bs := &p.boxVals[boxRow][boxCol]
-
-	// If the box's bitset is empty, populate it.
-	if bs.Len() == 0 || val == 0 {
-		// Calculate the box's puzzle coordinates (top left position).
-		boxRow, boxCol := boxRow*p.boxHeight, boxCol*p.boxWidth
-
-		// Iterate over all cells in the current box.
-		for row = 0; row < p.boxHeight; row++ {
-			for col = 0; col < p.boxWidth; col++ {
-				bs.Set(int(p.Arr[boxRow+row][boxCol+col]), 1)
+// populate resets the unit bitsets to the values of the underlying array.
+func (p Puzzle) populate() {
+	for u := range p.unitVals {
+		p.unitVals[u].Reset()
+	}
+	for row, vals := range p.Arr {
+		for col, val := range vals {
+			for _, u := range p.cellUnits[p.cellIndex(PuzzleInt(row), PuzzleInt(col))] {
+				p.unitVals[u].Set(int(val), 1)
 			}
 		}
 	}
-	// Return true if val is found in the set.
-	return bs.Get(int(val)) == 1
 }
 
 // isValidPos returns whether or not the value at the row and col position follows the
-// constraints of the Sudoku puzzle. The constraints are that a digit can only appear once
-// in a row, column, and box. In addition, a position must be vacant.
+// constraints of the Sudoku puzzle. The constraints are that a digit can only appear once in
+// each unit of the position, such as its row, column, and box (see Constraint), and that the cage
+// of the position, if any, can still add up to its sum. In addition, a position must be vacant.
 func (p Puzzle) isValidPos(row, col, val PuzzleInt) bool {
-	return int(row) < len(p.Arr) && int(col) < len(p.Arr[0]) && // Bounds check
-		p.Arr[row][col] == 0 && // The position must be vacant
-		!p.rowContains(row, val) && // Another row position should not have the same value
-		!p.colContains(col, val) && // Another column position should not have the same value
-		!p.boxContains(row, col, val) // Another box position should not have the same value
+	if int(row) >= len(p.Arr) || int(col) >= len(p.Arr[0]) || // Bounds check
+		p.Arr[row][col] != 0 { // The position must be vacant
+		return false
+	}
+	// Another position of each unit should not have the same value.
+	for _, u := range p.cellUnits[p.cellIndex(row, col)] {
+		if p.unitVals[u].Get(int(val)) == 1 {
+			return false
+		}
+	}
+	var cage bitSet
+	p.cageTaken(row, col, &cage)
+	return cage.Get(int(val)) == 0
 }
 
 // nextEmptyPos returns the next unoccupied position of the puzzle. If there are none, ok is false.
@@ -181,49 +217,130 @@ func (p Puzzle) nextEmptyPos(startRow, startCol PuzzleInt) (row, col PuzzleInt,
 
 // Solve solves modifies the underlying array to solve the Sudoku puzzle recursively, backtracking
 // when an invalid value is guessed, until a solution is found. Solve returns true when a puzzle is
-// successfully solved, otherwise, the puzzle was unsolvable.
+// successfully solved, otherwise, the puzzle was unsolvable or the search exceeded its budget (see
+// SolveContext).
 func (p Puzzle) Solve() bool {
-	// TODO: validate puzzle before solving.
-	return p.solve(0, 0)
+	_, err := p.SolveContext(context.Background())
+	return err == nil
+}
+
+// SolveContext solves the puzzle like Solve, stopping the search once ctx is done or the budget
+// of the puzzle is exceeded (see WithMaxNodes and WithMaxDuration). Puzzles with conflicting
+// values (see Validate) are rejected before searching. The search is done by the solver of the
+// puzzle (see WithSolver), which backtracks in the search order of the puzzle (see
+// WithSearchOrder) by default.
+//
+// SolveContext returns the statistics of the search, and an error wrapping ErrUnsolvable,
+// ErrBudgetExceeded, or the error of ctx when no solution was found, in which case the vacant
+// positions are left vacant.
+func (p Puzzle) SolveContext(ctx context.Context) (Result, error) {
+	res, err := p.solver.Solve(ctx, &p)
+	// Solvers may only fill the underlying array, so bring the bitsets up to date.
+	p.populate()
+	return res, err
+}
+
+// Solved returns a solved copy of the puzzle, with its own matrix and bitsets, leaving the puzzle
+// and its underlying array untouched. ok is false when the copy could not be solved (see Solve).
+func (p Puzzle) Solved() (solution Puzzle, ok bool) {
+	solution, _, err := p.SolveCopy(context.Background())
+	return solution, err == nil
+}
+
+// SolveCopy solves a copy of the puzzle like SolveContext, leaving the puzzle and its underlying
+// array untouched. The solved copy is returned with the statistics of the search. On error, the
+// copy is returned unsolved.
+func (p Puzzle) SolveCopy(ctx context.Context) (Puzzle, Result, error) {
+	c := p.clone()
+	res, err := c.SolveContext(ctx)
+	return c, res, err
 }
-func (p Puzzle) solve(row, col PuzzleInt) bool {
+
+// solve recursively fills the empty positions from the row and col position onwards, in row
+// major order, calling found on s for every solution. solve returns true when the search should
+// stop, leaving the solution in place.
+func (p Puzzle) solve(s *search, row, col PuzzleInt) bool {
+	// Stop when canceled or over budget.
+	if s.visit() {
+		return true
+	}
 	// Find the next empty position, if any.
 	row, col, ok := p.nextEmptyPos(row, col)
 	if !ok {
-		// No empty position, the puzzle is solved if it is valid.
-		return true
+		// No empty position, a solution was found.
+		return s.found()
 	}
 
+	// The values of the position's units.
+	taken := p.taken(row, col)
+
 	// Try all possible values, recurse, and backtrack.
 	for val := PuzzleInt(1); val <= PuzzleInt(len(p.Arr)); val++ {
-		// Validate position for the current value.
-		if !p.isValidPos(row, col, val) {
+		// Skip values already in a unit, such as the row, column, or box.
+		if taken.Get(int(val)) == 1 {
 			continue
 		}
 		// Set the value.
-		p.Arr[row][col] = val
-		// Update bitsets.
-		boxRow, boxCol := p.boxIndex(row, col)
-		p.boxVals[boxRow][boxCol].Set(int(val), 1)
-		p.rowVals[row].Set(int(val), 1)
-		p.colVals[col].Set(int(val), 1)
-
-		// Try to solve this path by recursing, return if that path was successful.
-		if ok := p.solve(row, col); ok {
+		p.set(row, col, val)
+
+		// Try to solve this path by recursing, return if the search is over.
+		if stop := p.solve(s, row, col); stop {
 			return true
 		}
-		// The path was not successful, reset position (backtrack).
-		p.Arr[row][col] = 0
-		// Reset bitsets.
-		p.boxVals[boxRow][boxCol].Set(int(val), 0)
-		p.rowVals[row].Set(int(val), 0)
-		p.colVals[col].Set(int(val), 0)
+		// The path was exhausted, reset position (backtrack).
+		p.unset(row, col, val)
+		s.Backtracks++
 	}
 
 	// Already attempted all possible values for this position.
 	return false
 }
 
+// BoxDimensions returns the height and width of the boxes of the puzzle. The single box of
+// jigsaw puzzles spans the puzzle (see WithRegions).
+func (p Puzzle) BoxDimensions() (height, width PuzzleInt) {
+	return p.boxHeight, p.boxWidth
+}
+
+// set places val at the row and col position, updating the bitsets.
+func (p Puzzle) set(row, col, val PuzzleInt) {
+	p.Arr[row][col] = val
+	for _, u := range p.cellUnits[p.cellIndex(row, col)] {
+		p.unitVals[u].Set(int(val), 1)
+	}
+}
+
+// unset vacates the row and col position which holds val, resetting the bitsets.
+func (p Puzzle) unset(row, col, val PuzzleInt) {
+	p.Arr[row][col] = 0
+	for _, u := range p.cellUnits[p.cellIndex(row, col)] {
+		p.unitVals[u].Set(int(val), 0)
+	}
+}
+
+// clone returns a deep copy of the puzzle, including its matrix and bitsets, such that
+// altering the copy does not affect the original puzzle.
+func (p Puzzle) clone() Puzzle {
+	c := p
+	c.Arr = make([][]PuzzleInt, len(p.Arr))
+	for i, row := range p.Arr {
+		c.Arr[i] = append([]PuzzleInt(nil), row...)
+	}
+	// The units are never altered, so only their bitsets are copied.
+	c.unitVals = cloneBitSets(p.unitVals)
+	return c
+}
+
+// withArr returns a puzzle with the configuration of p, but with arr as its underlying matrix
+// and newly populated bitsets. arr must have the same dimensions as the matrix of p.
+func (p Puzzle) withArr(arr [][]PuzzleInt) Puzzle {
+	c := p
+	c.Arr = arr
+	c.unitVals = make([]bitSet, len(p.unitVals))
+	c.populate()
+	return c
+}
+
 // String implements the Stringer interface for Puzzle by encoding into JSON.
 func (p Puzzle) String() string {
 	b, err := json.Marshal(p.Arr)
@@ -234,9 +351,15 @@ func (p Puzzle) String() string {
 }
 
 // Pretty returns a formatted string representation of the Sudoku puzzle for human
-// readability.
+// readability. The cells of the diagonals of X-Sudoku puzzles (see WithDiagonals) are marked
+// with a '*' after their value. Jigsaw puzzles (see WithRegions) are drawn with the borders of
+// their regions in place of box lines, leaving their diagonals unmarked.
 func (p Puzzle) Pretty() string {
+	if p.regions != nil {
+		return p.prettyRegions()
+	}
 	sb := strings.Builder{}
+	diagonals := p.hasConstraint(Diagonals)
 	// Calculate the number of horizontal lines.
 	hLines := int(math.Max(0, float64(len(p.Arr)/int(p.boxWidth)-1)))
 
@@ -258,7 +381,12 @@ func (p Puzzle) Pretty() string {
 			} else {
 				sb.WriteString(fmt.Sprintf("%d", v))
 			}
-			sb.WriteByte(' ')
+			// Mark the cells of the diagonals in place of the space after them.
+			if diagonals && (i == j || i+j == len(p.Arr)-1) {
+				sb.WriteByte('*')
+			} else {
+				sb.WriteByte(' ')
+			}
 		}
 		sb.WriteByte('\n')
 	}
@@ -1,15 +1,28 @@
 package sudoku
 
 import (
+	"context"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 )
 
+// engines lists the options of every search engine, which must behave identically.
+var engines = []struct {
+	name string
+	opts []PuzzleOption
+}{
+	{"row_major", []PuzzleOption{WithSearchOrder(RowMajor)}},
+	{"min_remaining_values", []PuzzleOption{WithSearchOrder(MinRemainingValues)}},
+	{"dancing_links", []PuzzleOption{WithSolver(DancingLinks)}},
+	{"parallel", []PuzzleOption{WithSolver(NewParallel(2, 4))}},
+}
+
 func TestSolve(t *testing.T) {
 	testCases := []struct {
 		puzzle     [][]PuzzleInt
-		puzzleOpts []puzzleOption
+		puzzleOpts []PuzzleOption
 		solved     [][]PuzzleInt
 	}{
 		{
@@ -69,7 +82,7 @@ func TestSolve(t *testing.T) {
 				{0, 0, 5, 0, 0, 0},
 				{0, 0, 0, 0, 1, 3},
 			},
-			puzzleOpts: []puzzleOption{
+			puzzleOpts: []PuzzleOption{
 				WithBoxDimensions(2, 3),
 			},
 			solved: [][]PuzzleInt{
@@ -83,9 +96,78 @@ func TestSolve(t *testing.T) {
 		},
 	}
 	for _, tc := range testCases {
-		puzzle := NewPuzzle(tc.puzzle, tc.puzzleOpts...)
-		require.True(t, puzzle.Solve())
-		require.Equal(t, tc.solved, tc.puzzle)
+		// Every engine finds the same solution.
+		for _, engine := range engines {
+			arr := copyArr(tc.puzzle)
+			puzzle, err := NewPuzzle(arr, append(tc.puzzleOpts, engine.opts...)...)
+			require.NoError(t, err)
+			require.True(t, puzzle.Solve(), engine.name)
+			require.Equal(t, tc.solved, arr, engine.name)
+		}
+	}
+}
+
+// copyArr returns a deep copy of a puzzle matrix.
+func copyArr(arr [][]PuzzleInt) [][]PuzzleInt {
+	c := make([][]PuzzleInt, len(arr))
+	for i, row := range arr {
+		c[i] = append([]PuzzleInt(nil), row...)
+	}
+	return c
+}
+
+func TestNewPuzzleErrors(t *testing.T) {
+	testCases := []struct {
+		arr        [][]PuzzleInt
+		puzzleOpts []PuzzleOption
+		err        error
+	}{
+		{
+			arr: [][]PuzzleInt{},
+			err: ErrNoRows,
+		},
+		{
+			arr: [][]PuzzleInt{{}},
+			err: ErrNotSquare,
+		},
+		{
+			arr: [][]PuzzleInt{{0, 0, 0}, {0, 0, 0}},
+			err: ErrNotSquare,
+		},
+		{
+			arr: [][]PuzzleInt{{0, 0}, {0}},
+			err: ErrRaggedRows,
+		},
+		{
+			arr: [][]PuzzleInt{{0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 5}},
+			err: ErrValueRange,
+		},
+		{
+			arr:        make([][]PuzzleInt, 6),
+			puzzleOpts: []PuzzleOption{WithBoxDimensions(4, 3)},
+			err:        ErrBoxDimensions,
+		},
+		{
+			arr:        make([][]PuzzleInt, 6),
+			puzzleOpts: []PuzzleOption{WithBoxDimensions(2, 7)},
+			err:        ErrBoxDimensions,
+		},
+		{
+			// Boxes that divide the side but hold more cells than a side has values.
+			arr:        make([][]PuzzleInt, 9),
+			puzzleOpts: []PuzzleOption{WithBoxDimensions(3, 9)},
+			err:        ErrBoxDimensions,
+		},
+	}
+	for _, tc := range testCases {
+		// Fill empty rows to form a square.
+		for i := range tc.arr {
+			if tc.arr[i] == nil {
+				tc.arr[i] = make([]PuzzleInt, len(tc.arr))
+			}
+		}
+		_, err := NewPuzzle(tc.arr, tc.puzzleOpts...)
+		require.ErrorIs(t, err, tc.err)
 	}
 }
 
@@ -94,7 +176,8 @@ func TestString(t *testing.T) {
 	for i := range arr {
 		arr[i] = make([]PuzzleInt, 9)
 	}
-	puzzle := NewPuzzle(arr)
+	puzzle, err := NewPuzzle(arr)
+	require.NoError(t, err)
 	require.Equal(t, puzzle.String(), "[[0,0,0,0,0,0,0,0,0],[0,0,0,0,0,0,0,0,0],[0,0,0,0,0,0,0,0,0],[0,0,0,0,0,0,0,0,0],[0,0,0,0,0,0,0,0,0],[0,0,0,0,0,0,0,0,0],[0,0,0,0,0,0,0,0,0],[0,0,0,0,0,0,0,0,0],[0,0,0,0,0,0,0,0,0]]")
 }
 
@@ -128,7 +211,8 @@ func TestRowContains(t *testing.T) {
 		},
 	}
 	for _, tc := range testCases {
-		puzzle := NewPuzzle(tc.arr)
+		puzzle, err := NewPuzzle(tc.arr)
+		require.NoError(t, err)
 		for _, test := range tc.tests {
 			require.Equal(t, test.expected, puzzle.rowContains(test.row, test.val))
 		}
@@ -165,7 +249,8 @@ func TestColContains(t *testing.T) {
 		},
 	}
 	for _, tc := range testCases {
-		puzzle := NewPuzzle(tc.arr)
+		puzzle, err := NewPuzzle(tc.arr)
+		require.NoError(t, err)
 		for _, test := range tc.tests {
 			require.Equal(t, test.expected, puzzle.colContains(test.col, test.val))
 		}
@@ -203,7 +288,8 @@ func TestBoxContains(t *testing.T) {
 		},
 	}
 	for _, tc := range testCases {
-		puzzle := NewPuzzle(tc.arr)
+		puzzle, err := NewPuzzle(tc.arr)
+		require.NoError(t, err)
 		for _, test := range tc.tests {
 			require.Equal(t, test.expected, puzzle.boxContains(test.row, test.col, test.val))
 		}
@@ -242,7 +328,8 @@ func TestValidPos(t *testing.T) {
 		},
 	}
 	for _, tc := range testCases {
-		puzzle := NewPuzzle(tc.arr)
+		puzzle, err := NewPuzzle(tc.arr)
+		require.NoError(t, err)
 		for _, test := range tc.tests {
 			require.Equal(t, test.expected, puzzle.isValidPos(test.row, test.col, test.val))
 		}
@@ -272,7 +359,9 @@ func TestNextEmptyPos(t *testing.T) {
 		},
 	}
 	for _, tc := range testCases {
-		puzzle := NewPuzzle(tc.arr)
+		// A side without a square root needs boxes of a single row.
+		puzzle, err := NewPuzzle(tc.arr, WithBoxDimensions(1, PuzzleInt(len(tc.arr))))
+		require.NoError(t, err)
 		var startRow, startCol PuzzleInt
 		for _, test := range tc.tests {
 			row, col, ok := puzzle.nextEmptyPos(startRow, startCol)
@@ -286,3 +375,98 @@ func TestNextEmptyPos(t *testing.T) {
 		}
 	}
 }
+
+// benchPuzzle returns a puzzle made of boxHeight x boxWidth boxes, from a random complete grid
+// with the passed ratio of its positions vacated. The puzzle is reproducible, but may have more
+// than one solution.
+func benchPuzzle(boxHeight, boxWidth PuzzleInt, vacant float64) [][]PuzzleInt {
+	r := rand.New(rand.NewSource(1))
+	arr := randomGrid(r, boxHeight, boxWidth)
+	for _, row := range arr {
+		for col := range row {
+			if r.Float64() < vacant {
+				row[col] = 0
+			}
+		}
+	}
+	return arr
+}
+
+func BenchmarkSolve(b *testing.B) {
+	benchmarks := []struct {
+		name                string
+		boxHeight, boxWidth PuzzleInt
+		vacant              float64
+	}{
+		{"4x4", 2, 2, 0.75},
+		{"9x9", 3, 3, 0.6},
+		{"16x16", 4, 4, 0.5},
+		{"25x25", 5, 5, 0.4},
+	}
+	for _, bm := range benchmarks {
+		arr := benchPuzzle(bm.boxHeight, bm.boxWidth, bm.vacant)
+		for _, engine := range engines {
+			opts := append([]PuzzleOption{WithBoxDimensions(bm.boxHeight, bm.boxWidth)}, engine.opts...)
+			b.Run(bm.name+"/"+engine.name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					// Solve a fresh copy of the puzzle every iteration.
+					b.StopTimer()
+					puzzle, err := NewPuzzle(copyArr(arr), opts...)
+					if err != nil {
+						b.Fatal(err)
+					}
+					b.StartTimer()
+
+					if !puzzle.Solve() {
+						b.Fatal("could not solve puzzle")
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestSolved(t *testing.T) {
+	arr := [][]PuzzleInt{
+		{0, 2, 3, 4},
+		{3, 4, 0, 2},
+		{2, 0, 4, 3},
+		{4, 3, 2, 0},
+	}
+	original := copyArr(arr)
+	puzzle, err := NewPuzzle(arr)
+	require.NoError(t, err)
+
+	solution, ok := puzzle.Solved()
+	require.True(t, ok)
+	require.Equal(t, [][]PuzzleInt{
+		{1, 2, 3, 4},
+		{3, 4, 1, 2},
+		{2, 1, 4, 3},
+		{4, 3, 2, 1},
+	}, solution.Arr)
+	require.True(t, solution.rowContains(0, 1))
+
+	// The puzzle, its underlying array, and its bitsets are untouched.
+	require.Equal(t, original, arr)
+	require.Equal(t, original, puzzle.Arr)
+	require.False(t, puzzle.rowContains(0, 1))
+
+	// Editing the copy does not affect the puzzle either.
+	solution.Arr[0][0] = 0
+	require.Equal(t, original, arr)
+
+	// Unsolvable puzzles return an unsolved copy.
+	unsolvable, err := NewPuzzle([][]PuzzleInt{
+		{1, 2, 0, 0},
+		{0, 0, 3, 0},
+		{0, 0, 0, 4},
+		{0, 0, 0, 0},
+	}, WithMaxNodes(100))
+	require.NoError(t, err)
+	c, res, err := unsolvable.SolveCopy(context.Background())
+	require.ErrorIs(t, err, ErrUnsolvable)
+	require.Zero(t, res.Solutions)
+	require.Equal(t, unsolvable.Arr, c.Arr)
+}
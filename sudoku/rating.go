@@ -0,0 +1,91 @@
+package sudoku
+
+import "errors"
+
+// ErrUnsolvable is returned when a puzzle has no solution.
+var ErrUnsolvable = errors.New("puzzle has no solution")
+
+// techniqueWeights maps a Technique to its weight in the score of a rating, with harder
+// techniques weighing more.
+var techniqueWeights = map[Technique]int{
+	NakedSingle:      1,
+	HiddenSingle:     2,
+	NakedPair:        5,
+	PointingPair:     5,
+	HiddenPair:       6,
+	BoxLineReduction: 6,
+	NakedTriple:      7,
+	HiddenTriple:     8,
+	XWing:            15,
+	Swordfish:        18,
+	XYWing:           20,
+	Guess:            40,
+}
+
+// hardestFactor is the factor of the hardest technique's weight in the score, such that the
+// hardest technique dominates the number of steps.
+const hardestFactor = 100
+
+// standardCells is the number of cells of a 9x9 puzzle, which the weights of the steps of other
+// puzzles are scaled to, such that the bands of the score hold for any side.
+const standardCells = 81
+
+// scoreBands lists the exclusive upper bound of the score of each Difficulty, from easiest to
+// hardest. Scores above the last bound are Expert.
+var scoreBands = []struct {
+	difficulty Difficulty
+	maxScore   int
+}{
+	{Easy, 500},    // Singles
+	{Medium, 1500}, // Subsets and intersections
+	{Hard, 4000},   // Fish and wings
+}
+
+// Rating represents the difficulty of a puzzle, derived from a logical solve.
+type Rating struct {
+	// Score is the weight of the hardest technique times hardestFactor, plus the weight of
+	// every step, scaled from the cells of the puzzle to those of a 9x9 puzzle.
+	Score      int               `json:"score"`
+	Difficulty Difficulty        `json:"difficulty"`
+	Hardest    Technique         `json:"hardest"`
+	Counts     map[Technique]int `json:"counts"`
+}
+
+// difficultyOf returns the Difficulty band of a score.
+func difficultyOf(score int) Difficulty {
+	for _, band := range scoreBands {
+		if score < band.maxScore {
+			return band.difficulty
+		}
+	}
+	return Expert
+}
+
+// Rate grades the puzzle by solving it logically (see SolveLogical), scoring the hardest
+// technique needed and how often each technique is used. Rate returns ErrUnsolvable if the
+// puzzle has no solution, and ErrBudgetExceeded if guessing needs a search that exceeds the
+// budget of the puzzle (see SolveContext). The puzzle is left untouched.
+func (p Puzzle) Rate() (Rating, error) {
+	s := newLogicalSolver(p)
+	res := s.run()
+	if !res.Solved {
+		if errors.Is(s.err, ErrBudgetExceeded) {
+			return Rating{}, s.err
+		}
+		return Rating{}, ErrUnsolvable
+	}
+
+	rating := Rating{Counts: make(map[Technique]int)}
+	steps := 0
+	for _, step := range res.Steps {
+		rating.Counts[step.Technique]++
+		steps += techniqueWeights[step.Technique]
+		if techniqueWeights[step.Technique] > techniqueWeights[rating.Hardest] {
+			rating.Hardest = step.Technique
+		}
+	}
+	// Larger puzzles take more steps of the same techniques, so the steps are scaled by size.
+	rating.Score = hardestFactor*techniqueWeights[rating.Hardest] + steps*standardCells/(len(p.Arr)*len(p.Arr))
+	rating.Difficulty = difficultyOf(rating.Score)
+	return rating, nil
+}
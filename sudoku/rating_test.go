@@ -0,0 +1,101 @@
+package sudoku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRate(t *testing.T) {
+	testCases := []struct {
+		line       string
+		hardest    Technique
+		difficulty Difficulty
+	}{
+		{
+			line:       "003020600900305001001806400008102900700000008006708200002609500800203009005010300",
+			hardest:    NakedSingle,
+			difficulty: Easy,
+		},
+		{
+			line:       "100000569492056108056109240009640801064010000218035604040500016905061402621000005",
+			hardest:    XWing,
+			difficulty: Hard,
+		},
+		{
+			line:       "800400910003000000000003004000001040058000700070006800000002000000000160910060500",
+			hardest:    Guess,
+			difficulty: Expert,
+		},
+		{
+			// Larger puzzles take more steps, which must not raise their difficulty.
+			line: "..3...4.86.2A..9" + "...6GF79...D3..." + ".....5.E.4B.26.." + "..BF...D..79.4.." +
+				"2.1.A....G......" + "98....374.5..G.." + ".E6......7F....1" + ".A.G.......B42D5" +
+				"7..1.E.5..D...F." + "....C7.3.....15." + ".F.4D..A....9..." + "E....G.BA.C48..3" +
+				"A.....CG6B...E3." + "....8.....4E..1." + ".6........2....." + "548...6....3.CG.",
+			hardest:    HiddenSingle,
+			difficulty: Easy,
+		},
+		{
+			line: "M.3.B.K.8.O..F.9.LI.HNP.D" + ".2...FH...IKL.G..EMO3..9." +
+				"F.J.P....L.8..H3..K..7BC." + "...L...BE.6.5...7....4..." +
+				"8...H.3.D.24.CE...NP1K.M." + "2D..4.M6.IF.J.K.........." +
+				"9E.ML..1J....A.HK.C.56..." + ".H5.A....E...OCJ.I43...FP" +
+				".8....9.L7..EH..NO....I.." + ".IN..38FC.9..G..1.D....H." +
+				".4.13...6....5.G.M...C9.8" + "E......2..BA.8LDH....F5ON" +
+				"....KP...O3..4.8B..NG...I" + "...G.I..7..O.P.4....D...L" +
+				"..FA.5B...E1..J...7.432.." + "4.K.16...87.F..PACH..EO.." +
+				"7AM.....2.L...8ID.J5K..1H" + "JF......1.M.......L97.35." +
+				"....N.FH9.AP..O7.....B.DM" + "G..DEA5.P.4B.....3.8.LC2F" +
+				"3..4..6..JN...FE.HO.....K" + "....8.D3..P..BAF945......" +
+				"A.GO........C..NID6..M..5" + "B......KA.G.H..L..P2..E4C" +
+				"NC.P.E.............1.8.A.",
+			hardest:    HiddenSingle,
+			difficulty: Easy,
+		},
+	}
+	for _, tc := range testCases {
+		puzzle, err := ParseLine(tc.line)
+		require.NoError(t, err)
+
+		rating, err := puzzle.Rate()
+		require.NoError(t, err)
+		require.Equal(t, tc.hardest, rating.Hardest)
+		require.Equal(t, tc.difficulty, rating.Difficulty)
+
+		// The score is derived from the hardest technique and the count of each technique, scaled
+		// to the cells of a 9x9 puzzle.
+		steps := 0
+		for tech, count := range rating.Counts {
+			steps += count * techniqueWeights[tech]
+		}
+		cells := len(puzzle.Arr) * len(puzzle.Arr)
+		require.Equal(t, hardestFactor*techniqueWeights[tc.hardest]+steps*standardCells/cells, rating.Score)
+		if cells > standardCells {
+			// Scaling lowers the scores of larger puzzles.
+			require.Less(t, rating.Score, hardestFactor*techniqueWeights[tc.hardest]+steps)
+		}
+	}
+}
+
+func TestRateUnsolvable(t *testing.T) {
+	puzzle, err := NewPuzzle([][]PuzzleInt{
+		{1, 2, 0, 0},
+		{0, 0, 3, 4},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	})
+	require.NoError(t, err)
+
+	_, err = puzzle.Rate()
+	require.ErrorIs(t, err, ErrUnsolvable)
+}
+
+func TestRateBudget(t *testing.T) {
+	// Needs guessing, which takes more than 10 nodes.
+	puzzle, err := NewPuzzle(lineArr("800400910003000000000003004000001040058000700070006800000002000000000160910060500", 9),
+		WithMaxNodes(10))
+	require.NoError(t, err)
+	_, err = puzzle.Rate()
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+}
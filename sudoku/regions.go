@@ -0,0 +1,162 @@
+package sudoku
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrRegions is returned when a region map does not match the puzzle dimensions, or has regions
+// that are out of range, of the wrong size, or not connected.
+var ErrRegions = errors.New("malformed region map")
+
+// regionConstraint requires every value to appear once in each region of a jigsaw puzzle, in
+// place of its boxes (see WithRegions). Regions are reported as boxes.
+type regionConstraint struct{}
+
+func (regionConstraint) Type() UnitType { return UnitBox }
+
+func (regionConstraint) Units(p Puzzle) [][]Cell {
+	units := make([][]Cell, len(p.Arr))
+	for row, ids := range p.regions {
+		for col, id := range ids {
+			units[id] = append(units[id], Cell{PuzzleInt(row), PuzzleInt(col)})
+		}
+	}
+	return units
+}
+
+// Regions returns a copy of the region map of a jigsaw puzzle, or nil for puzzles with boxes (see
+// WithRegions).
+func (p Puzzle) Regions() [][]PuzzleInt {
+	if p.regions == nil {
+		return nil
+	}
+	regions := make([][]PuzzleInt, len(p.regions))
+	for i, ids := range p.regions {
+		regions[i] = append([]PuzzleInt(nil), ids...)
+	}
+	return regions
+}
+
+// buildRegions validates the region map of the puzzle, if any, and replaces the boxes of the
+// puzzle with its regions. An error wrapping ErrRegions is returned if the map is malformed.
+func (p *Puzzle) buildRegions() error {
+	if p.regions == nil {
+		return nil
+	}
+	side := len(p.Arr)
+	if len(p.regions) != side {
+		return fmt.Errorf("%w: %d rows, expected %d", ErrRegions, len(p.regions), side)
+	}
+	sizes := make([]int, side)
+	for row, ids := range p.regions {
+		if len(ids) != side {
+			return fmt.Errorf("%w: row %d has %d columns, expected %d", ErrRegions, row, len(ids), side)
+		}
+		for col, id := range ids {
+			if int(id) >= side {
+				return fmt.Errorf("%w: region %d at row %d, column %d, expected less than %d", ErrRegions,
+					id, row, col, side)
+			}
+			sizes[id]++
+		}
+	}
+	for id, size := range sizes {
+		if size != side {
+			return fmt.Errorf("%w: region %d has %d cells, expected %d", ErrRegions, id, size, side)
+		}
+	}
+
+	// Every region has the right size, so a region is connected if its cells are all reached from
+	// any one of them.
+	seen := make([]bool, side*side)
+	for row, ids := range p.regions {
+		for col, id := range ids {
+			if seen[p.cellIndex(PuzzleInt(row), PuzzleInt(col))] {
+				continue
+			}
+			if n := p.fillRegion(seen, row, col); n != side {
+				return fmt.Errorf("%w: region %d is not connected", ErrRegions, id)
+			}
+		}
+	}
+
+	for i, c := range p.constraints {
+		if c == Boxes {
+			p.constraints[i] = regionConstraint{}
+		}
+	}
+	return nil
+}
+
+// fillRegion marks the cells of the region of the row and col position that are connected to it
+// horizontally or vertically as seen, returning their number.
+func (p Puzzle) fillRegion(seen []bool, row, col int) int {
+	side := len(p.Arr)
+	id := p.regions[row][col]
+	n := 0
+	stack := []Cell{{PuzzleInt(row), PuzzleInt(col)}}
+	seen[p.cellIndex(PuzzleInt(row), PuzzleInt(col))] = true
+	for len(stack) != 0 {
+		cell := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		n++
+		for _, d := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+			r, c := int(cell.Row)+d[0], int(cell.Col)+d[1]
+			if r < 0 || r >= side || c < 0 || c >= side || p.regions[r][c] != id ||
+				seen[p.cellIndex(PuzzleInt(r), PuzzleInt(c))] {
+				continue
+			}
+			seen[p.cellIndex(PuzzleInt(r), PuzzleInt(c))] = true
+			stack = append(stack, Cell{PuzzleInt(r), PuzzleInt(c)})
+		}
+	}
+	return n
+}
+
+// prettyRegions returns the Pretty representation of a jigsaw puzzle (see prettyBorders).
+func (p Puzzle) prettyRegions() string {
+	return prettyBorders(p.Arr, func(row, col int) int {
+		return int(p.regions[row][col])
+	})
+}
+
+// prettyBorders returns a formatted string representation of arr, where the cells of a row are
+// separated by '|' when they belong to different regions, and the rows are separated by a line
+// with '-' under the cells that belong to a different region than the cell below. region returns
+// the region of the row and col position, or -1 for positions without a cell, which are left
+// blank and without borders.
+func prettyBorders(arr [][]PuzzleInt, region func(row, col int) int) string {
+	sb := strings.Builder{}
+	// border returns whether or not a and b are cells of different regions.
+	border := func(a, b int) bool {
+		return a != -1 && b != -1 && a != b
+	}
+	for i, row := range arr {
+		if i != 0 {
+			for j := range row {
+				if border(region(i-1, j), region(i, j)) {
+					sb.WriteString("- ")
+				} else {
+					sb.WriteString("  ")
+				}
+			}
+			sb.WriteByte('\n')
+		}
+		for j, v := range row {
+			if v == 0 {
+				sb.WriteByte(' ')
+			} else {
+				sb.WriteString(fmt.Sprintf("%d", v))
+			}
+			if j != len(row)-1 && border(region(i, j), region(i, j+1)) {
+				sb.WriteByte('|')
+			} else {
+				sb.WriteByte(' ')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
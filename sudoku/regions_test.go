@@ -0,0 +1,137 @@
+package sudoku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// jigsaw4x4 is a region map of a 4x4 puzzle, with 96 solutions when empty.
+var jigsaw4x4 = [][]PuzzleInt{
+	{0, 0, 0, 1},
+	{2, 0, 1, 1},
+	{2, 2, 3, 1},
+	{2, 3, 3, 3},
+}
+
+func TestRegions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		line     string
+		solution string
+	}{
+		{
+			// Regions need not be rectangular, so neither does the side.
+			name:     "5x5",
+			line:     "0001100211322213342433444:.24.5...............1....",
+			solution: "0001100211322213342433444:3241551342241534352115234",
+		},
+		{
+			name:     "6x6",
+			line:     "000111000111422333442353422355442555:4....1..2..6.6.......51...4.........",
+			solution: "000111000111422333442353422355442555:456321312456265143643512124635531264",
+		},
+		{
+			name: "9x9",
+			line: "000111222000111222003141222303144555333444555633774585636774885666774888666777888:" +
+				".......9..9..3....56.....37..41....2.....3.7.....4.......5....3....94.6.75.......",
+			solution: "000111222000111222003141222303144555333444555633774585636774885666774888666777888:" +
+				"143687295297435618568912437384176952619253874975348126421569783832794561756821349",
+		},
+	}
+	for _, tc := range testCases {
+		for _, engine := range engines {
+			t.Run(tc.name+"/"+engine.name, func(t *testing.T) {
+				puzzle, err := ParseLine(tc.line, engine.opts...)
+				require.NoError(t, err)
+				require.True(t, puzzle.HasUniqueSolution())
+
+				solution, ok := puzzle.Solved()
+				require.True(t, ok)
+				text, err := solution.MarshalText()
+				require.NoError(t, err)
+				require.Equal(t, tc.solution, string(text))
+				require.Empty(t, solution.Validate())
+			})
+		}
+
+		t.Run(tc.name+"/logical", func(t *testing.T) {
+			puzzle, err := ParseLine(tc.line)
+			require.NoError(t, err)
+			res := puzzle.SolveLogical()
+			require.True(t, res.Solved)
+			solution, err := ParseLine(tc.solution)
+			require.NoError(t, err)
+			require.Equal(t, solution.Arr, res.Arr)
+		})
+	}
+
+	for _, engine := range engines {
+		t.Run("count/"+engine.name, func(t *testing.T) {
+			puzzle, err := NewPuzzle(emptyArr(4), append([]PuzzleOption{WithRegions(jigsaw4x4)}, engine.opts...)...)
+			require.NoError(t, err)
+			require.Equal(t, 96, puzzle.CountSolutions(0))
+		})
+	}
+}
+
+func TestRegionsValidate(t *testing.T) {
+	arr := emptyArr(4)
+	arr[0][2], arr[1][1] = 3, 3 // The same region, but different boxes
+	arr[2][3], arr[3][2] = 4, 4 // The same box, but different regions
+	puzzle, err := NewPuzzle(arr, WithRegions(jigsaw4x4))
+	require.NoError(t, err)
+	require.Equal(t, []Conflict{
+		{Unit: UnitBox, Value: 3, A: Cell{0, 2}, B: Cell{1, 1}},
+	}, puzzle.Validate())
+	require.Equal(t, jigsaw4x4, puzzle.Regions())
+
+	h, w := puzzle.BoxDimensions()
+	require.Equal(t, PuzzleInt(4), h)
+	require.Equal(t, PuzzleInt(4), w)
+}
+
+func TestRegionsPretty(t *testing.T) {
+	arr := [][]PuzzleInt{
+		{1, 0, 0, 4},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{4, 0, 0, 1},
+	}
+	puzzle, err := NewPuzzle(arr, WithRegions(jigsaw4x4))
+	require.NoError(t, err)
+	require.Equal(t, ""+
+		"1    |4 \n"+
+		"-   -   \n"+
+		" | |    \n"+
+		"  - -   \n"+
+		"   | |  \n"+
+		"  -   - \n"+
+		"4|    1 \n", puzzle.Pretty())
+}
+
+func TestRegionErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		regions [][]PuzzleInt
+	}{
+		{"missing row", jigsaw4x4[:3]},
+		{"ragged", [][]PuzzleInt{{0, 0, 0, 1}, {2, 0, 1, 1}, {2, 2, 3, 1}, {2, 3, 3}}},
+		{"out of range", [][]PuzzleInt{{0, 0, 0, 1}, {2, 0, 1, 1}, {2, 2, 3, 1}, {2, 3, 3, 4}}},
+		{"wrong size", [][]PuzzleInt{{0, 0, 0, 1}, {2, 0, 1, 1}, {2, 2, 3, 1}, {2, 3, 3, 1}}},
+		{"not connected", [][]PuzzleInt{{0, 0, 0, 1}, {2, 1, 0, 1}, {2, 2, 3, 1}, {2, 3, 3, 3}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewPuzzle(emptyArr(4), WithRegions(tc.regions))
+			require.ErrorIs(t, err, ErrRegions)
+		})
+	}
+}
+
+func TestRegionsCanonical(t *testing.T) {
+	puzzle, err := NewPuzzle(emptyArr(4), WithRegions(jigsaw4x4))
+	require.NoError(t, err)
+	_, err = puzzle.Canonical()
+	require.ErrorIs(t, err, ErrCanonicalVariant)
+}
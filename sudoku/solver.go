@@ -0,0 +1,207 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Result represents the outcome and statistics of a search.
+type Result struct {
+	// Solutions is the number of solutions found.
+	Solutions int `json:"solutions"`
+	// Nodes is the number of nodes of the search tree visited.
+	Nodes int `json:"nodes"`
+	// Backtracks is the number of values removed after their branch of the search was exhausted.
+	Backtracks int           `json:"backtracks"`
+	Elapsed    time.Duration `json:"elapsed"`
+}
+
+// Solver represents a search engine that solves puzzles. The solver of a puzzle is set with
+// WithSolver, and is used by Solve.
+type Solver interface {
+	// Solve fills the vacant positions of p with the first solution found. ErrUnsolvable is
+	// returned if the values of p conflict or lead to no solution, ErrBudgetExceeded if the
+	// budget of p is exceeded, and the error of ctx if it is done before a solution is found. The
+	// vacant positions of p are left vacant on error.
+	Solve(ctx context.Context, p *Puzzle) (Result, error)
+}
+
+// Counter is implemented by solvers that can count the solutions of a puzzle, which
+// CountSolutions uses. Solvers that do not implement Counter are counted by Backtracking.
+type Counter interface {
+	// Count counts the solutions of p, stopping once limit solutions are found, unless limit is
+	// not positive. Puzzles with conflicting values have no solutions. ErrBudgetExceeded is
+	// returned if the budget of p is exceeded, and the error of ctx if it is done, with the
+	// solutions found so far. p is left untouched.
+	Count(ctx context.Context, p *Puzzle, limit int) (Result, error)
+}
+
+// Built-in solvers, which implement both Solver and Counter.
+var (
+	// Backtracking fills vacant positions one at a time, in the search order of the puzzle (see
+	// WithSearchOrder), backtracking when a position has no possible value. It is the default.
+	Backtracking Solver = backtracking{}
+	// DancingLinks solves the puzzle as an exact cover problem, using Knuth's Algorithm X with
+	// Dancing Links. It is best suited for large puzzles.
+	DancingLinks Solver = dancingLinks{}
+)
+
+// ErrBudgetExceeded is returned when a search visits more nodes or takes longer than the budget
+// of the puzzle (see WithMaxNodes and WithMaxDuration).
+var ErrBudgetExceeded = errors.New("search budget exceeded")
+
+// checkInterval is the number of nodes visited between checks of the context and the deadline of
+// a search, which are costly relative to visiting a node.
+const checkInterval = 1 << 10
+
+// budget represents the limits of a search, where zero limits are unlimited.
+type budget struct {
+	maxNodes    int
+	maxDuration time.Duration
+}
+
+// search represents the state of a search by a built-in solver.
+type search struct {
+	Result
+	limit int    // Solutions to find before stopping, all if not positive
+	total *int64 // Solutions found across concurrent searches, if shared
+
+	ctx      context.Context
+	budget   budget
+	deadline time.Time // Zero without a maximum duration
+	err      error     // Reason the search was interrupted
+}
+
+// found records a solution, returning whether or not the search should stop.
+func (s *search) found() bool {
+	s.Solutions++
+	n := int64(s.Solutions)
+	if s.total != nil {
+		n = atomic.AddInt64(s.total, 1)
+	}
+	return s.limit > 0 && n >= int64(s.limit)
+}
+
+// visit records a node of the search tree, returning whether or not the search must be
+// interrupted, as it was canceled or exceeded its budget. The reason is kept in err.
+func (s *search) visit() bool {
+	s.Nodes++
+	if s.budget.maxNodes > 0 && s.Nodes > s.budget.maxNodes {
+		s.err = fmt.Errorf("%w: more than %d nodes", ErrBudgetExceeded, s.budget.maxNodes)
+		return true
+	}
+	if s.Nodes%checkInterval != 0 {
+		return false
+	}
+	if err := s.ctx.Err(); err != nil {
+		s.err = err
+		return true
+	}
+	if !s.deadline.IsZero() && time.Now().After(s.deadline) {
+		s.err = fmt.Errorf("%w: longer than %v", ErrBudgetExceeded, s.budget.maxDuration)
+		return true
+	}
+	return false
+}
+
+// searchFunc searches the solutions of a puzzle, calling found on the search state for every
+// solution, and leaving the solution it stopped at in place. The search stops as soon as visit
+// returns true.
+type searchFunc func(p Puzzle, s *search)
+
+// solveWith fills the vacant positions of p with the first solution found by fn, within the
+// budget of p.
+func solveWith(ctx context.Context, p *Puzzle, fn searchFunc) (Result, error) {
+	if len(p.Validate()) != 0 {
+		return Result{}, ErrUnsolvable
+	}
+	res, err := runSearch(ctx, *p, 1, budget{p.maxNodes, p.maxDuration}, fn)
+	if err == nil && res.Solutions == 0 {
+		err = ErrUnsolvable
+	}
+	return res, err
+}
+
+// countWith counts the solutions of p found by fn, up to limit, within the budget of p.
+func countWith(ctx context.Context, p *Puzzle, limit int, fn searchFunc) (Result, error) {
+	if len(p.Validate()) != 0 {
+		return Result{}, nil
+	}
+	// Search a copy, leaving Arr and the bitsets untouched.
+	return runSearch(ctx, p.clone(), limit, budget{p.maxNodes, p.maxDuration}, fn)
+}
+
+// runSearch runs fn on p within b, timing it. If the search is interrupted, the positions it
+// filled are vacated.
+func runSearch(ctx context.Context, p Puzzle, limit int, b budget, fn searchFunc) (Result, error) {
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+	// Keep the vacant positions to restore them after an interruption.
+	var vacant []Cell
+	for row := range p.Arr {
+		for col, val := range p.Arr[row] {
+			if val == 0 {
+				vacant = append(vacant, Cell{PuzzleInt(row), PuzzleInt(col)})
+			}
+		}
+	}
+
+	start := time.Now()
+	s := search{limit: limit, ctx: ctx, budget: b}
+	if b.maxDuration > 0 {
+		s.deadline = start.Add(b.maxDuration)
+	}
+	fn(p, &s)
+	s.Elapsed = time.Since(start)
+
+	if s.err != nil {
+		for _, c := range vacant {
+			p.Arr[c.Row][c.Col] = 0
+		}
+		p.populate()
+		return s.Result, s.err
+	}
+	return s.Result, nil
+}
+
+// backtracking implements the Backtracking solver.
+type backtracking struct{}
+
+// Solve implements the Solver interface for backtracking.
+func (b backtracking) Solve(ctx context.Context, p *Puzzle) (Result, error) {
+	return solveWith(ctx, p, b.search)
+}
+
+// Count implements the Counter interface for backtracking.
+func (b backtracking) Count(ctx context.Context, p *Puzzle, limit int) (Result, error) {
+	return countWith(ctx, p, limit, b.search)
+}
+
+func (backtracking) search(p Puzzle, s *search) {
+	if p.searchOrder == MinRemainingValues {
+		p.solveMRV(s)
+		return
+	}
+	p.solve(s, 0, 0)
+}
+
+// dancingLinks implements the DancingLinks solver.
+type dancingLinks struct{}
+
+// Solve implements the Solver interface for dancingLinks.
+func (d dancingLinks) Solve(ctx context.Context, p *Puzzle) (Result, error) {
+	return solveWith(ctx, p, d.search)
+}
+
+// Count implements the Counter interface for dancingLinks.
+func (d dancingLinks) Count(ctx context.Context, p *Puzzle, limit int) (Result, error) {
+	return countWith(ctx, p, limit, d.search)
+}
+
+func (dancingLinks) search(p Puzzle, s *search) {
+	newDLX(p).search(s)
+}
@@ -0,0 +1,175 @@
+package sudoku
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// solutionSolver is a Solver that fills the underlying array with a fixed solution, without
+// updating the bitsets.
+type solutionSolver [][]PuzzleInt
+
+func (s solutionSolver) Solve(ctx context.Context, p *Puzzle) (Result, error) {
+	for i, row := range s {
+		copy(p.Arr[i], row)
+	}
+	return Result{Solutions: 1}, nil
+}
+
+func TestSolverResult(t *testing.T) {
+	// Needs guessing.
+	arr := lineArr("800400910003000000000003004000001040058000700070006800000002000000000160910060500", 9)
+	solvers := []struct {
+		name   string
+		solver Solver
+	}{
+		{"backtracking", Backtracking},
+		{"dancing_links", DancingLinks},
+	}
+	for _, s := range solvers {
+		puzzle, err := NewPuzzle(copyArr(arr))
+		require.NoError(t, err)
+
+		res, err := s.solver.Solve(context.Background(), &puzzle)
+		require.NoError(t, err, s.name)
+		require.Equal(t, 1, res.Solutions, s.name)
+		require.Positive(t, res.Nodes, s.name)
+		require.Positive(t, res.Backtracks, s.name)
+		require.Positive(t, int64(res.Elapsed), s.name)
+		require.Empty(t, puzzle.Validate(), s.name)
+		_, _, ok := puzzle.nextEmptyPos(0, 0)
+		require.False(t, ok, s.name)
+
+		// Counting leaves the puzzle untouched.
+		puzzle, err = NewPuzzle(copyArr(arr))
+		require.NoError(t, err)
+		res, err = s.solver.(Counter).Count(context.Background(), &puzzle, 0)
+		require.NoError(t, err, s.name)
+		require.Equal(t, 1, res.Solutions, s.name)
+		require.Equal(t, arr, puzzle.Arr, s.name)
+	}
+}
+
+func TestSolverErrors(t *testing.T) {
+	for _, solver := range []Solver{Backtracking, DancingLinks} {
+		// Conflicting values.
+		conflicting, err := NewPuzzle([][]PuzzleInt{
+			{1, 1, 0, 0},
+			{0, 0, 0, 0},
+			{0, 0, 0, 0},
+			{0, 0, 0, 0},
+		})
+		require.NoError(t, err)
+		_, err = solver.Solve(context.Background(), &conflicting)
+		require.ErrorIs(t, err, ErrUnsolvable)
+
+		// No conflicts, but no value fits the top right position.
+		unsolvable, err := NewPuzzle([][]PuzzleInt{
+			{1, 2, 0, 0},
+			{0, 0, 3, 0},
+			{0, 0, 0, 4},
+			{0, 0, 0, 0},
+		})
+		require.NoError(t, err)
+		res, err := solver.Solve(context.Background(), &unsolvable)
+		require.ErrorIs(t, err, ErrUnsolvable)
+		require.Zero(t, res.Solutions)
+		require.Equal(t, PuzzleInt(0), unsolvable.Arr[0][2])
+
+		// Canceled before searching.
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		puzzle, err := NewPuzzle(emptyArr(4))
+		require.NoError(t, err)
+		_, err = solver.Solve(ctx, &puzzle)
+		require.ErrorIs(t, err, context.Canceled)
+	}
+}
+
+func TestWithSolver(t *testing.T) {
+	solution := [][]PuzzleInt{
+		{1, 2, 3, 4},
+		{3, 4, 1, 2},
+		{2, 1, 4, 3},
+		{4, 3, 2, 1},
+	}
+	puzzle, err := NewPuzzle(emptyArr(4), WithSolver(solutionSolver(solution)))
+	require.NoError(t, err)
+	require.True(t, puzzle.Solve())
+	require.Equal(t, solution, puzzle.Arr)
+	// The bitsets follow the values the solver placed.
+	require.True(t, puzzle.rowContains(0, 4))
+
+	// Solvers without a Counter are counted by backtracking.
+	puzzle, err = NewPuzzle(emptyArr(4), WithSolver(solutionSolver(solution)))
+	require.NoError(t, err)
+	require.Equal(t, 288, puzzle.CountSolutions(0))
+}
+
+// doneContext is a context that is canceled after its error is checked n times.
+type doneContext struct {
+	context.Context
+	n int
+}
+
+func (ctx *doneContext) Err() error {
+	if ctx.n <= 0 {
+		return context.Canceled
+	}
+	ctx.n--
+	return nil
+}
+
+func TestSolveContextBudget(t *testing.T) {
+	// Needs guessing.
+	arr := lineArr("800400910003000000000003004000001040058000700070006800000002000000000160910060500", 9)
+	testCases := []struct {
+		name string
+		ctx  func() context.Context
+		opts []PuzzleOption
+		err  error
+	}{
+		{"max_nodes", context.Background, []PuzzleOption{WithMaxNodes(10)}, ErrBudgetExceeded},
+		{"max_duration", context.Background, []PuzzleOption{WithMaxDuration(time.Nanosecond)}, ErrBudgetExceeded},
+		{
+			// Canceled during the search, after the check before searching.
+			"canceled",
+			func() context.Context { return &doneContext{context.Background(), 1} },
+			nil,
+			context.Canceled,
+		},
+	}
+	for _, tc := range testCases {
+		for _, engine := range engines {
+			if engine.name == "parallel" {
+				// Workers are budgeted separately, see TestParallelBudget.
+				continue
+			}
+			name := tc.name + "/" + engine.name
+			puzzle, err := NewPuzzle(copyArr(arr), append(tc.opts, engine.opts...)...)
+			require.NoError(t, err)
+			res, err := puzzle.SolveContext(tc.ctx())
+			if res.Nodes < checkInterval && tc.name != "max_nodes" {
+				// Solved before the context or the deadline were checked.
+				require.NoError(t, err, name)
+				continue
+			}
+			require.ErrorIs(t, err, tc.err, name)
+			require.Zero(t, res.Solutions, name)
+
+			// The puzzle is restored, including its bitsets.
+			require.Equal(t, arr, puzzle.Arr, name)
+			original, err := NewPuzzle(copyArr(arr))
+			require.NoError(t, err)
+			require.Equal(t, original.CandidateGrid(), puzzle.CandidateGrid(), name)
+		}
+	}
+
+	// Solve reports the failure.
+	puzzle, err := NewPuzzle(copyArr(arr), WithMaxNodes(10))
+	require.NoError(t, err)
+	require.False(t, puzzle.Solve())
+}
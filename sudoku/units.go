@@ -0,0 +1,60 @@
+package sudoku
+
+import "fmt"
+
+// Cell represents the position of a cell in a puzzle.
+type Cell struct {
+	Row PuzzleInt `json:"row"`
+	Col PuzzleInt `json:"col"`
+}
+
+// UnitType represents the kind of a unit, a group of cells where a digit may only appear once.
+type UnitType int
+
+// Unit types of a standard Sudoku puzzle, followed by those of variants.
+const (
+	UnitRow UnitType = iota
+	UnitColumn
+	UnitBox
+	UnitDiagonal
+	UnitCage
+)
+
+// unitTypeNames maps a UnitType to its name.
+var unitTypeNames = map[UnitType]string{
+	UnitRow:      "row",
+	UnitColumn:   "column",
+	UnitBox:      "box",
+	UnitDiagonal: "diagonal",
+	UnitCage:     "cage",
+}
+
+// String implements the Stringer interface for UnitType.
+func (t UnitType) String() string {
+	if name, ok := unitTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("UnitType(%d)", int(t))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for UnitType, encoding it by name.
+func (t UnitType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for UnitType, decoding it by name.
+func (t *UnitType) UnmarshalText(text []byte) error {
+	for ut, name := range unitTypeNames {
+		if name == string(text) {
+			*t = ut
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown unit type %q", text)
+}
+
+// unit represents a group of cells where a digit may only appear once.
+type unit struct {
+	Type  UnitType
+	Cells []Cell
+}
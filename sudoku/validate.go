@@ -0,0 +1,52 @@
+package sudoku
+
+// Conflict represents a pair of cells in the same unit that hold the same value. Conflicts of the
+// sum of a cage have no Value, and hold the sum of the values of the cage instead.
+type Conflict struct {
+	Unit  UnitType  `json:"unit"`
+	Value PuzzleInt `json:"value"`
+	A     Cell      `json:"a"`
+	B     Cell      `json:"b"`
+	Sum   int       `json:"sum,omitempty"`
+}
+
+// Validate checks the values of the puzzle against its constraints (see Constraint), returning
+// every pair of cells that hold the same value in a unit. Conflicts are ordered by unit (rows,
+// columns, boxes, then the units of other constraints) and by position within a unit. Cages whose
+// values exceed their sum, or fill them without adding up to it, follow as a conflict between
+// their first and last occupied cells (see WithCages). A puzzle with conflicts is unsolvable,
+// while a puzzle without conflicts may still be unsolvable.
+func (p Puzzle) Validate() []Conflict {
+	var conflicts []Conflict
+	for _, u := range p.units {
+		// Group the occupied cells of the unit by value.
+		seen := make(map[PuzzleInt][]Cell)
+		for _, c := range u.Cells {
+			val := p.Arr[c.Row][c.Col]
+			if val == 0 {
+				continue
+			}
+			// Pair the cell with every previous cell of the same value.
+			for _, prev := range seen[val] {
+				conflicts = append(conflicts, Conflict{Unit: u.Type, Value: val, A: prev, B: c})
+			}
+			seen[val] = append(seen[val], c)
+		}
+	}
+
+	for _, cage := range p.cages {
+		var occupied []Cell
+		sum := 0
+		for _, c := range cage.Cells {
+			if val := p.Arr[c.Row][c.Col]; val != 0 {
+				occupied = append(occupied, c)
+				sum += int(val)
+			}
+		}
+		if sum > cage.Sum || (len(occupied) == len(cage.Cells) && sum != cage.Sum) {
+			conflicts = append(conflicts, Conflict{Unit: UnitCage, A: occupied[0], B: occupied[len(occupied)-1],
+				Sum: sum})
+		}
+	}
+	return conflicts
+}
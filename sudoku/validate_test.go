@@ -0,0 +1,68 @@
+package sudoku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		arr        [][]PuzzleInt
+		puzzleOpts []PuzzleOption
+		conflicts  []Conflict
+	}{
+		{
+			arr: [][]PuzzleInt{
+				{8, 0, 0, 4, 0, 0, 9, 1, 0},
+				{0, 0, 3, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 3, 0, 0, 4},
+				{0, 0, 0, 0, 0, 1, 0, 4, 0},
+				{0, 5, 8, 0, 0, 0, 7, 0, 0},
+				{0, 7, 0, 0, 0, 6, 8, 0, 0},
+				{0, 0, 0, 0, 0, 2, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 1, 6, 0},
+				{9, 1, 0, 0, 6, 0, 5, 0, 0},
+			},
+		},
+		{
+			arr: [][]PuzzleInt{
+				{5, 0, 0, 0, 0, 5},
+				{0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0},
+				{0, 2, 0, 0, 0, 0},
+				{0, 0, 2, 0, 0, 5},
+			},
+			puzzleOpts: []PuzzleOption{WithBoxDimensions(2, 3)},
+			conflicts: []Conflict{
+				{Unit: UnitRow, Value: 5, A: Cell{0, 0}, B: Cell{0, 5}},
+				{Unit: UnitColumn, Value: 5, A: Cell{0, 5}, B: Cell{5, 5}},
+				{Unit: UnitBox, Value: 2, A: Cell{4, 1}, B: Cell{5, 2}},
+			},
+		},
+		{
+			// Every pair of a repeated value is reported.
+			arr: [][]PuzzleInt{
+				{1, 1, 0, 1},
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+			},
+			conflicts: []Conflict{
+				{Unit: UnitRow, Value: 1, A: Cell{0, 0}, B: Cell{0, 1}},
+				{Unit: UnitRow, Value: 1, A: Cell{0, 0}, B: Cell{0, 3}},
+				{Unit: UnitRow, Value: 1, A: Cell{0, 1}, B: Cell{0, 3}},
+				{Unit: UnitBox, Value: 1, A: Cell{0, 0}, B: Cell{0, 1}},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		puzzle, err := NewPuzzle(tc.arr, tc.puzzleOpts...)
+		require.NoError(t, err)
+		require.Equal(t, tc.conflicts, puzzle.Validate())
+		if len(tc.conflicts) != 0 {
+			require.False(t, puzzle.Solve())
+		}
+	}
+}